@@ -0,0 +1,169 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// asyncRecord is a record queued by the async OutPutter.
+type asyncRecord struct {
+	ctx    context.Context
+	name   string
+	level  Level
+	msg    string
+	fields []Field
+}
+
+// asyncOutPutter is an OutPutter which outputs records to the underlying
+// OutPutter in a background goroutine.
+type asyncOutPutter struct {
+	underlying OutPutter
+	drop       bool
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []asyncRecord
+	size     int
+	closed   bool
+	done     chan struct{}
+
+	unregister func()
+}
+
+// Resizer is implemented by buffering OutPutters whose buffer size can be
+// changed at runtime, i.e. the one created by NewAsyncOutPutter.
+type Resizer interface {
+	// Resize changes the buffer size to n records.
+	Resize(n int)
+}
+
+// AsyncOption is option for the OutPutter created by NewAsyncOutPutter.
+type AsyncOption func(a *asyncOutPutter)
+
+// WithAsyncDrop decides what the OutPutter does when the buffer is full: drop
+// the record if drop is true, or block the caller until there is room, which
+// is the default.
+func WithAsyncDrop(drop bool) AsyncOption {
+	return func(a *asyncOutPutter) {
+		a.drop = drop
+	}
+}
+
+// NewAsyncOutPutter create a OutPutter which queues records in a buffer of
+// bufferSize records, and outputs them to underlying in a background
+// goroutine, so that callers don't block on slow outputs. A bufferSize less
+// than 1 is taken as 1. The returned OutPutter implements Resizer.
+// The fields are copied with their Valuers resolved when the record is
+// queued, since the context they depend on may be gone when the record is
+// output. Caller information is not available to underlying.
+// The returned io.Closer outputs the queued records and stops the goroutine;
+// records output after it is closed are discarded. It is registered with
+// RegisterShutdown too, until it is closed.
+func NewAsyncOutPutter(underlying OutPutter, bufferSize int, opts ...AsyncOption) (OutPutter, io.Closer) {
+	a := &asyncOutPutter{
+		underlying: underlying,
+		done:       make(chan struct{}),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.Resize(bufferSize)
+	for _, opt := range opts {
+		opt(a)
+	}
+	go a.run()
+	a.unregister = RegisterShutdown(a.Close)
+	return a, a
+}
+
+func (a *asyncOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, _ int) {
+	rec := asyncRecord{ctx: ctx, name: name, level: level, msg: msg, fields: resolveFields(ctx, name, fields)}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for !a.closed && len(a.queue) >= a.size {
+		if a.drop {
+			return
+		}
+		a.notFull.Wait()
+	}
+	if a.closed {
+		return
+	}
+	a.queue = append(a.queue, rec)
+	a.notEmpty.Signal()
+}
+
+// resolveFields returns a copy of fields with their Valuers resolved, for
+// records outputted later. Fields with empty key are skipped.
+func resolveFields(ctx context.Context, name string, fields []Field) []Field {
+	resolved := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		if len(field.Key) == 0 {
+			continue
+		}
+		resolved = append(resolved, Field{field.Key, NamedValue(ctx, name, field.Value)})
+	}
+	return resolved
+}
+
+// Resize changes the buffer size to n records, n less than 1 is taken as 1.
+// Queued records are never dropped: if the buffer shrinks below the number of
+// queued records, it takes effect as the queue drains. It is safe to call
+// Resize concurrently with OutPut.
+func (a *asyncOutPutter) Resize(n int) {
+	if n < 1 {
+		n = 1
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.size = n
+	a.notFull.Broadcast()
+}
+
+// run outputs the queued records until the OutPutter is closed and the queue
+// is drained.
+func (a *asyncOutPutter) run() {
+	defer close(a.done)
+	for {
+		a.mu.Lock()
+		for len(a.queue) == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if len(a.queue) == 0 {
+			a.mu.Unlock()
+			return
+		}
+		rec := a.queue[0]
+		a.queue[0] = asyncRecord{}
+		a.queue = a.queue[1:]
+		a.notFull.Signal()
+		a.mu.Unlock()
+		if a.underlying != nil {
+			a.underlying.OutPut(rec.ctx, rec.name, rec.level, rec.msg, rec.fields, 0)
+		}
+	}
+}
+
+// Close outputs the queued records and stops the background goroutine. It is
+// safe to call Close more than once.
+func (a *asyncOutPutter) Close() error {
+	a.mu.Lock()
+	a.closed = true
+	a.notEmpty.Broadcast()
+	a.notFull.Broadcast()
+	a.mu.Unlock()
+	<-a.done
+	a.unregister()
+	return nil
+}
+
+func (a *asyncOutPutter) Describe() string {
+	return "async"
+}
+
+// Unwrap returns the wrapped OutPutter.
+func (a *asyncOutPutter) Unwrap() OutPutter {
+	return a.underlying
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// gateOutPutter signals started for each record and waits for release
+// before recording it.
+type gateOutPutter struct {
+	started chan struct{}
+	release chan struct{}
+	msgs    []string
+}
+
+func (g *gateOutPutter) OutPut(_ context.Context, _ string, _ Level, msg string, _ []Field, _ int) {
+	g.started <- struct{}{}
+	<-g.release
+	g.msgs = append(g.msgs, msg)
+}
+
+func TestNewAsyncOutPutter(t *testing.T) {
+	out := make(chanOutPutter, 16)
+	o, closer := NewAsyncOutPutter(out, 4)
+	user := "mike"
+	fields := []Field{
+		{"", "skipped"},
+		{"user", Valuer(func(ctx context.Context) interface{} { return user })},
+	}
+	o.OutPut(context.Background(), "pkg", InfoLevel, "a", fields, 0)
+	user = "jane"
+	fields[1].Value = "overwritten"
+	o.OutPut(context.Background(), "pkg", WarnLevel, "b", nil, 0)
+	if err := closer.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o.OutPut(context.Background(), "pkg", WarnLevel, "after close", nil, 0)
+	_ = closer.Close()
+	close(out)
+	var got []Record
+	for rec := range out {
+		got = append(got, rec)
+	}
+	expect := []Record{
+		{Name: "pkg", Level: InfoLevel, Msg: "a", Fields: []Field{{"user", "mike"}}},
+		{Name: "pkg", Level: WarnLevel, Msg: "b", Fields: []Field{}},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %+v, got %+v", expect, got)
+	}
+}
+
+func TestNewAsyncOutPutter_Drop(t *testing.T) {
+	gate := &gateOutPutter{started: make(chan struct{}, 4), release: make(chan struct{})}
+	o, closer := NewAsyncOutPutter(gate, 1, WithAsyncDrop(true))
+	o.OutPut(context.Background(), "", InfoLevel, "1", nil, 0)
+	<-gate.started
+	o.OutPut(context.Background(), "", InfoLevel, "2", nil, 0)
+	o.OutPut(context.Background(), "", InfoLevel, "3", nil, 0)
+	close(gate.release)
+	_ = closer.Close()
+	if expect := []string{"1", "2"}; !reflect.DeepEqual(gate.msgs, expect) {
+		t.Errorf("expect %v, got %v", expect, gate.msgs)
+	}
+}
+
+func TestNewAsyncOutPutter_Shutdown(t *testing.T) {
+	out := make(chanOutPutter, 16)
+	o, _ := NewAsyncOutPutter(out, 4)
+	o.OutPut(context.Background(), "", InfoLevel, "a", nil, 0)
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out) != 1 {
+		t.Errorf("expect the record flushed on Shutdown, got %d records", len(out))
+	}
+}
+
+func TestNewAsyncOutPutter_CloseUnregister(t *testing.T) {
+	registered := shutdownFnsLen()
+	_, closer := NewAsyncOutPutter(make(chanOutPutter, 16), 4)
+	if got := shutdownFnsLen(); got != registered+1 {
+		t.Errorf("expect %d registered, got %d", registered+1, got)
+	}
+	_ = closer.Close()
+	if got := shutdownFnsLen(); got != registered {
+		t.Errorf("expect %d registered after Close, got %d", registered, got)
+	}
+}
+
+// countOutPutter counts the records output.
+type countOutPutter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *countOutPutter) OutPut(_ context.Context, _ string, _ Level, _ string, _ []Field, _ int) {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+func TestNewAsyncOutPutter_Resize(t *testing.T) {
+	gate := &gateOutPutter{started: make(chan struct{}, 16), release: make(chan struct{})}
+	o, closer := NewAsyncOutPutter(gate, 1)
+	o.OutPut(context.Background(), "", InfoLevel, "1", nil, 0)
+	<-gate.started
+	o.OutPut(context.Background(), "", InfoLevel, "2", nil, 0)
+	queued := make(chan struct{})
+	go func() {
+		// blocks until the buffer grows
+		o.OutPut(context.Background(), "", InfoLevel, "3", nil, 0)
+		close(queued)
+	}()
+	o.(Resizer).Resize(2)
+	<-queued
+	o.(Resizer).Resize(0)
+	close(gate.release)
+	_ = closer.Close()
+	if expect := []string{"1", "2", "3"}; !reflect.DeepEqual(gate.msgs, expect) {
+		t.Errorf("expect %v, got %v", expect, gate.msgs)
+	}
+}
+
+func TestNewAsyncOutPutter_ResizeConcurrently(t *testing.T) {
+	out := &countOutPutter{}
+	o, closer := NewAsyncOutPutter(out, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+			}
+		}()
+	}
+	for _, n := range []int{64, 2, 128, 1, 16} {
+		o.(Resizer).Resize(n)
+	}
+	wg.Wait()
+	_ = closer.Close()
+	if out.n != 2000 {
+		t.Errorf("expect 2000 records, got %d", out.n)
+	}
+}
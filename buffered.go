@@ -0,0 +1,155 @@
+package log
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// bufferedOutPutter is an OutPutter which formats records into a buffer and
+// writes the buffer to the sink in batches.
+type bufferedOutPutter struct {
+	formatter  OutPutter
+	sink       io.Writer
+	flushBytes int
+	errHandler func(err error)
+
+	mu     sync.Mutex
+	buf    []byte
+	closed bool
+	// wmu serializes the writes to sink, in the order of the batches.
+	wmu  sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+
+	unregister func()
+}
+
+// bufferAppender is the io.Writer the formatter writes lines to, which
+// appends them to the buffer.
+type bufferAppender struct {
+	b *bufferedOutPutter
+}
+
+func (a bufferAppender) Write(p []byte) (int, error) {
+	a.b.mu.Lock()
+	defer a.b.mu.Unlock()
+	if !a.b.closed {
+		a.b.buf = append(a.b.buf, p...)
+	}
+	return len(p), nil
+}
+
+// NewBufferedOutPutter create a OutPutter which formats records like the
+// OutPutter created by NewStdOutPutter with out and opts, but holds the lines
+// in a buffer, and writes them in a batch to the writer of out when the
+// buffer reaches flushBytes, when flushInterval elapsed since the last batch,
+// or when it is closed. The prefix and flags of out are kept.
+//
+// Records are formatted by the calling goroutine, so Valuers are resolved and
+// caller information is taken at the time of OutPut. The buffer is swapped
+// under a lock and written outside it: loggers only wait for a write if
+// another batch fills while the previous one is being written. Write errors
+// are passed to the handler set by WithErrorHandler like NewStdOutPutter.
+//
+// A flushBytes less than 1 is taken as 1, which writes every record at once.
+// A flushInterval less than or equal to 0 disables flushing on interval.
+// Intervals are measured by the registered Clock (see UseClock).
+// The returned io.Closer writes the held lines and stops the goroutine;
+// records output after it is closed are discarded. It is registered with
+// RegisterShutdown too, until it is closed.
+func NewBufferedOutPutter(out *log.Logger, flushBytes int, flushInterval time.Duration,
+	opts ...StdOutPutterOption) (OutPutter, io.Closer) {
+	if out == nil {
+		out = log.Default()
+	}
+	if flushBytes < 1 {
+		flushBytes = 1
+	}
+	b := &bufferedOutPutter{
+		sink:       out.Writer(),
+		flushBytes: flushBytes,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	b.formatter = NewStdOutPutter(log.New(bufferAppender{b}, out.Prefix(), out.Flags()), opts...)
+	b.errHandler = b.formatter.(*stdOutPutter).errHandler
+	if flushInterval > 0 {
+		go b.run(getClock().NewTicker(flushInterval))
+	} else {
+		close(b.done)
+	}
+	b.unregister = RegisterShutdown(b.Close)
+	return b, b
+}
+
+func (b *bufferedOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	b.formatter.OutPut(ctx, name, level, msg, fields, callDepth+1)
+	b.flush(false)
+}
+
+// flush writes the buffer to the sink if it reaches flushBytes or force is
+// true. The buffer is swapped under mu, and written with only wmu held.
+func (b *bufferedOutPutter) flush(force bool) {
+	b.mu.Lock()
+	if len(b.buf) == 0 || !force && len(b.buf) < b.flushBytes {
+		b.mu.Unlock()
+		return
+	}
+	batch := b.buf
+	b.buf = make([]byte, 0, cap(batch))
+	b.wmu.Lock()
+	b.mu.Unlock()
+	defer b.wmu.Unlock()
+	if _, err := b.sink.Write(batch); err != nil {
+		if b.errHandler != nil {
+			b.errHandler(err)
+			return
+		}
+		handleInternalError(err)
+	}
+}
+
+// run flushes on each tick of ticker until the OutPutter is closed.
+func (b *bufferedOutPutter) run(ticker Ticker) {
+	defer close(b.done)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C():
+		}
+		b.flush(true)
+	}
+}
+
+// Close writes the held lines and stops the background goroutine. It is safe
+// to call Close more than once.
+func (b *bufferedOutPutter) Close() error {
+	b.mu.Lock()
+	closing := !b.closed
+	if closing {
+		b.closed = true
+		close(b.stop)
+	}
+	b.mu.Unlock()
+	if closing {
+		b.flush(true)
+	}
+	<-b.done
+	b.unregister()
+	return nil
+}
+
+func (b *bufferedOutPutter) Describe() string {
+	return "buffered"
+}
+
+// Unwrap returns the OutPutter formatting the records.
+func (b *bufferedOutPutter) Unwrap() OutPutter {
+	return b.formatter
+}
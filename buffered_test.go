@@ -0,0 +1,115 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeRecorder is an io.Writer recording each write.
+type writeRecorder struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *writeRecorder) get() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+func TestNewBufferedOutPutter(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	w := &writeRecorder{}
+	o, closer := NewBufferedOutPutter(log.New(w, "", 0), 16, time.Second)
+	ctx := context.Background()
+	user := Valuer(func(ctx context.Context) interface{} { return "mike" })
+	o.OutPut(ctx, "pkg", InfoLevel, "a", []Field{{"", "skipped"}, {"u", user}}, 0)
+	o.OutPut(ctx, "pkg", InfoLevel, "b", nil, 0)
+	if got := w.get(); len(got) != 0 {
+		t.Errorf("expect nothing written before reaching the size, got %q", got)
+	}
+	// 9+2 bytes held, and 6 more reach the size
+	o.OutPut(ctx, "pkg", WarnLevel, "cdefg", nil, 0)
+	expect := []string{"u=mike a\nb\ncdefg\n"}
+	if got := w.get(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %q written in a batch, got %q", expect, got)
+	}
+
+	o.OutPut(ctx, "pkg", InfoLevel, "i", nil, 0)
+	clock.tick(time.Second)
+	// the next tick is received after the flush of the previous one
+	clock.tick(time.Second)
+	expect = append(expect, "i\n")
+	if got := w.get(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %q written on interval, got %q", expect, got)
+	}
+
+	o.OutPut(ctx, "pkg", InfoLevel, "j", nil, 0)
+	_ = closer.Close()
+	expect = append(expect, "j\n")
+	if got := w.get(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %q written on close, got %q", expect, got)
+	}
+	o.OutPut(ctx, "pkg", InfoLevel, "after close", nil, 0)
+	_ = closer.Close()
+	if got := w.get(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect records discarded after close, got %q", got)
+	}
+}
+
+func TestNewBufferedOutPutter_Caller(t *testing.T) {
+	w := &bytes.Buffer{}
+	o, closer := NewBufferedOutPutter(log.New(w, "", log.Lshortfile), 1<<10, 0)
+	logger := NewStdLogger("", o)
+	_, _, line, _ := runtime.Caller(0)
+	logger.AtLevel(context.Background(), InfoLevel).Print("abc")
+	_ = closer.Close()
+	expect := fmt.Sprintf("buffered_test.go:%d: ", line+1)
+	if got := w.String(); !strings.HasPrefix(got, expect) {
+		t.Errorf("expect prefix %q, got %q", expect, got)
+	}
+}
+
+func TestNewBufferedOutPutter_WriteError(t *testing.T) {
+	var got []error
+	writeErr := errors.New("disk full")
+	o, closer := NewBufferedOutPutter(log.New(&failWriter{writeErr}, "", 0), 1, 0,
+		WithErrorHandler(func(err error) {
+			got = append(got, err)
+		}))
+	defer closer.Close()
+	o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+	if len(got) != 1 || got[0] != writeErr {
+		t.Errorf("expect %v handled, got %v", writeErr, got)
+	}
+}
+
+func TestNewBufferedOutPutter_CloseUnregister(t *testing.T) {
+	registered := shutdownFnsLen()
+	_, closer := NewBufferedOutPutter(log.New(&bytes.Buffer{}, "", 0), 1024, 0)
+	if got := shutdownFnsLen(); got != registered+1 {
+		t.Errorf("expect %d registered, got %d", registered+1, got)
+	}
+	_ = closer.Close()
+	if got := shutdownFnsLen(); got != registered {
+		t.Errorf("expect %d registered after Close, got %d", registered, got)
+	}
+}
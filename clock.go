@@ -0,0 +1,78 @@
+package log
+
+import (
+	"time"
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+// Clock is the source of time of the components of this package. It is
+// replaceable for testing, see UseClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTicker returns a Ticker ticking every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks of a Clock.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the Ticker.
+	Stop()
+}
+
+// systemClock is the Clock based on the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+// systemTicker is the Ticker based on time.Ticker.
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTicker) Stop() {
+	t.t.Stop()
+}
+
+var _clock = ua.NewUnsafePointer(unsafe.Pointer((*Clock)(nil)))
+
+// UseClock register the Clock used by this package. By default, or if nil is
+// passed, the system clock is used.
+// If this function is called more than once, the last call wins.
+func UseClock(clock Clock) {
+	_clock.Store(unsafe.Pointer(&clock))
+}
+
+// getClock returns the registered Clock.
+func getClock() Clock {
+	clock := (*Clock)(_clock.Load())
+	if clock == nil || *clock == nil {
+		return systemClock{}
+	}
+	return *clock
+}
+
+// clockNow returns the current time of the registered Clock.
+func clockNow() time.Time {
+	return getClock().Now()
+}
+
+// Now returns the current time of the registered Clock, for OutPutters of
+// other packages keeping the UseClock contract.
+func Now() time.Time {
+	return clockNow()
+}
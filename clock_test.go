@@ -0,0 +1,59 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time and ticks are driven by tests.
+type fakeClock struct {
+	now   time.Time
+	ticks chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		ticks: make(chan time.Time),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(_ time.Duration) Ticker {
+	return c
+}
+
+func (c *fakeClock) C() <-chan time.Time {
+	return c.ticks
+}
+
+func (c *fakeClock) Stop() {
+}
+
+// tick delivers a tick, it blocks until the tick is received.
+func (c *fakeClock) tick(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.ticks <- c.now
+}
+
+func TestUseClock(t *testing.T) {
+	defer UseClock(nil)
+	if _, ok := getClock().(systemClock); !ok {
+		t.Errorf("expect system clock by default, got %T", getClock())
+	}
+	clock := newFakeClock()
+	UseClock(clock)
+	if got := clockNow(); !got.Equal(clock.now) {
+		t.Errorf("expect %v, got %v", clock.now, got)
+	}
+	if got := Now(); !got.Equal(clock.now) {
+		t.Errorf("expect %v, got %v", clock.now, got)
+	}
+	UseClock(nil)
+	if _, ok := getClock().(systemClock); !ok {
+		t.Errorf("expect system clock after reset, got %T", getClock())
+	}
+}
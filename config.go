@@ -0,0 +1,119 @@
+package log
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Options configures the default LoggerProvider, the LevelStore and the level
+// names in one call. See Configure.
+type Options struct {
+	// OutPutter is used by the Loggers produced by the default
+	// LoggerProvider. If it is nil, an OutPutter based on log.Default() is
+	// used.
+	OutPutter OutPutter
+	// LoggerOptions are applied to every Logger produced by the default
+	// LoggerProvider.
+	LoggerOptions []StdLoggerOption
+	// LevelStore is bound to the Loggers produced by the default
+	// LoggerProvider (see WithLevelStore). If it is nil, the one returned by
+	// GetLevelStore is used.
+	LevelStore LevelStore
+	// Levels restores the levels of the LevelStore if it is not nil.
+	Levels map[string]Level
+	// LevelNames are registered as level names.
+	LevelNames map[Level]string
+	// Fields are attached to every record output.
+	Fields []Field
+}
+
+// Configure validates the options and then configures the default
+// LoggerProvider, the LevelStore and the level names accordingly. If the
+// options are invalid, an error aggregating all problems is returned and
+// nothing is changed.
+func Configure(opts Options) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	for level, name := range opts.LevelNames {
+		RegisterLevelName(level, name)
+	}
+	store := opts.LevelStore
+	if store == nil {
+		store = GetLevelStore()
+	}
+	if opts.Levels != nil && store != nil {
+		store.Restore(opts.Levels)
+	}
+	out := opts.OutPutter
+	if out == nil {
+		out = NewStdOutPutter(log.Default())
+	}
+	if len(opts.Fields) > 0 {
+		out = FilterAddFields(out, opts.Fields...)
+	}
+	loggerOpts := opts.LoggerOptions
+	if opts.LevelStore != nil {
+		loggerOpts = append([]StdLoggerOption{WithLevelStore(opts.LevelStore)}, loggerOpts...)
+	}
+	UseProvider(NewStdLoggerProvider(out, loggerOpts...))
+	return nil
+}
+
+func (opts Options) validate() error {
+	var errs errorList
+	levels := make([]Level, 0, len(opts.LevelNames))
+	for level := range opts.LevelNames {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	seen := map[string]Level{}
+	for _, level := range levels {
+		name := opts.LevelNames[level]
+		if len(name) == 0 {
+			errs = append(errs, fmt.Errorf("empty name for level %d", level))
+			continue
+		}
+		if other, ok := seen[strings.ToUpper(name)]; ok {
+			errs = append(errs, fmt.Errorf("name %q for both level %d and %d", name, other, level))
+			continue
+		}
+		seen[strings.ToUpper(name)] = level
+	}
+	for i, field := range opts.Fields {
+		if len(field.Key) == 0 {
+			errs = append(errs, fmt.Errorf("empty key for field #%d", i))
+		}
+	}
+	return errs.err()
+}
+
+// RestoreLevelStrings parses the levels by ParseLevel, and restores the
+// LevelStore with them, i.e. levels from configuration systems like
+// `{"": "info", "pkg/db": "debug"}`. If any level can't be parsed, an error
+// identifying all the offending names is returned and the LevelStore is not
+// changed.
+func RestoreLevelStrings(store LevelStore, levels map[string]string) error {
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs errorList
+	parsed := make(map[string]Level, len(levels))
+	for _, name := range names {
+		level, err := ParseLevel(levels[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("level of %q: %w", name, err))
+			continue
+		}
+		parsed[name] = level
+	}
+	if err := errs.err(); err != nil {
+		return err
+	}
+	store.Restore(parsed)
+	return nil
+}
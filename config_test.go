@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestConfigure(t *testing.T) {
+	op := getLoggerProvider()
+	levels := GetLevelStore().Levels()
+	defer func() {
+		UseProvider(op)
+		GetLevelStore().Restore(levels)
+		resetLevelNames()
+	}()
+	w := &bytes.Buffer{}
+	err := Configure(Options{
+		OutPutter:     NewStdOutPutter(log.New(w, "", 0)),
+		LoggerOptions: []StdLoggerOption{WithPrintlnSeparator(",")},
+		Levels: map[string]Level{
+			"":    WarnLevel,
+			"pkg": Level(5),
+		},
+		LevelNames: map[Level]string{Level(5): "NOTICE"},
+		Fields:     []Field{{"app", "demo"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	Get("xyz").AtLevel(context.Background(), InfoLevel).Print("nothing")
+	Get("pkg/sub").AtLevel(context.Background(), Level(5)).Println("a", "b")
+	expect := "app=demo level=NOTICE logger=pkg/sub a,b\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestConfigure_LevelStore(t *testing.T) {
+	op := getLoggerProvider()
+	defer UseProvider(op)
+	w := &bytes.Buffer{}
+	store := NewLevelStore(nil)
+	err := Configure(Options{
+		OutPutter:  NewStdOutPutter(log.New(w, "", 0)),
+		LevelStore: store,
+		Levels:     map[string]Level{"": DebugLevel},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := GetLevelStore().Get(""); got != InfoLevel {
+		t.Errorf("expect the global LevelStore not affected, got %v", got)
+	}
+	Get("xyz").AtLevel(context.Background(), DebugLevel).Print("abc")
+	expect := "level=DEBUG logger=xyz abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestConfigure_Invalid(t *testing.T) {
+	op := getLoggerProvider()
+	defer UseProvider(op)
+	err := Configure(Options{
+		Levels: map[string]Level{"": ErrorLevel},
+		LevelNames: map[Level]string{
+			Level(5): "",
+			Level(6): "notice",
+			Level(7): "NOTICE",
+		},
+		Fields: []Field{{"", "value"}},
+	})
+	expect := `empty name for level 5; name "NOTICE" for both level 6 and 7; empty key for field #0`
+	if err == nil || err.Error() != expect {
+		t.Errorf("expect %q, got %v", expect, err)
+	}
+	if got := GetLevelStore().Get(""); got != InfoLevel {
+		t.Errorf("expect nothing changed, got root level %v", got)
+	}
+	if getLoggerProvider() == nil {
+		t.Errorf("expect provider not changed")
+	}
+}
+
+func TestRestoreLevelStrings(t *testing.T) {
+	store := newTestLevelStore()
+	err := RestoreLevelStrings(store, map[string]string{"": "info", "pkg/db": "Debug"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := map[string]Level{"": InfoLevel, "pkg/db": DebugLevel}
+	if got := store.Levels(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %v, got %v", expect, got)
+	}
+	err = RestoreLevelStrings(store, map[string]string{"": "warn", "pkg/a": "verbose", "pkg/b": "loud"})
+	if err == nil {
+		t.Fatalf("expect error, got nil")
+	}
+	if msg := `level of "pkg/a": unknown level "verbose"; level of "pkg/b": unknown level "loud"`; err.Error() != msg {
+		t.Errorf("expect %q, got %q", msg, err.Error())
+	}
+	if got := store.Levels(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect levels unchanged %v, got %v", expect, got)
+	}
+}
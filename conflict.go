@@ -0,0 +1,70 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+
+	ua "go.uber.org/atomic"
+)
+
+var (
+	_checkLevelConflict = ua.NewBool(false)
+	// _diagnosticOutput is where the diagnostics are written to.
+	_diagnosticOutput io.Writer = os.Stderr
+)
+
+// SetLevelConflictCheck enable or disable the development check of level
+// conflicts. Once enabled, when the level of a logger name is set to a
+// different level by a different call site than the one set it before, a
+// one-time diagnostic is written to the standard error. It usually means two
+// parts of the codebase use the same logger name by accident.
+// It is disabled by default, and is cheap when disabled.
+func SetLevelConflictCheck(enable bool) {
+	_checkLevelConflict.Store(enable)
+}
+
+// levelSetter records who set the level of a logger name.
+type levelSetter struct {
+	level  Level
+	caller string
+}
+
+// levelConflicts tracks the setters of levels and the reported conflicts.
+type levelConflicts struct {
+	mu       sync.Mutex
+	setters  map[string]levelSetter
+	reported map[string]bool
+}
+
+// check records the setter of the level of name, and reports a conflict if
+// a different call site set a different level before. The skip is the number
+// of stack frames to ascend to the call site, with 0 identifying the caller
+// of check.
+func (c *levelConflicts) check(name string, level Level, skip int) {
+	if !_checkLevelConflict.Load() {
+		return
+	}
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return
+	}
+	setter := levelSetter{level: level, caller: fmt.Sprintf("%s:%d", file, line)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.setters == nil {
+		c.setters = map[string]levelSetter{}
+		c.reported = map[string]bool{}
+	}
+	prev, ok := c.setters[name]
+	c.setters[name] = setter
+	if !ok || prev.level == level || prev.caller == setter.caller || c.reported[name] {
+		return
+	}
+	c.reported[name] = true
+	_, _ = fmt.Fprintf(_diagnosticOutput,
+		"log: level of logger %q set to %v at %s, conflicting with %v set at %s\n",
+		name, level, setter.caller, prev.level, prev.caller)
+}
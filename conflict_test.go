@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+func newTestLevelStore() *stdLevelStore {
+	return &stdLevelStore{
+		store: ua.NewUnsafePointer(unsafe.Pointer(&levelSnapshot{
+			levels: map[string]Level{"": InfoLevel},
+		})),
+	}
+}
+
+func setDebugFromModuleA(store LevelStore) {
+	store.Set("shared", DebugLevel)
+}
+
+func setErrorFromModuleB(store LevelStore) {
+	store.Set("shared", ErrorLevel)
+}
+
+func TestSetLevelConflictCheck(t *testing.T) {
+	w := &bytes.Buffer{}
+	_diagnosticOutput = w
+	defer func() {
+		_diagnosticOutput = os.Stderr
+		SetLevelConflictCheck(false)
+	}()
+
+	store := newTestLevelStore()
+	setDebugFromModuleA(store)
+	setErrorFromModuleB(store)
+	if w.Len() != 0 {
+		t.Errorf("expect no diagnostic when disabled, got %q", w.String())
+	}
+
+	SetLevelConflictCheck(true)
+	store = newTestLevelStore()
+	setDebugFromModuleA(store)
+	setDebugFromModuleA(store)
+	store.Set("shared", DebugLevel)
+	if w.Len() != 0 {
+		t.Errorf("expect no diagnostic for the same level, got %q", w.String())
+	}
+	setErrorFromModuleB(store)
+	got := w.String()
+	if !strings.Contains(got, `logger "shared" set to ERROR`) || !strings.Contains(got, "conflict_test.go") {
+		t.Errorf("expect a conflict diagnostic, got %q", got)
+	}
+	w.Reset()
+	setDebugFromModuleA(store)
+	if w.Len() != 0 {
+		t.Errorf("expect the diagnostic reported only once, got %q", w.String())
+	}
+}
+
+func setDebugViaSetLevel() {
+	SetLevel("shared.helper", DebugLevel)
+}
+
+func setErrorViaSetLevel() {
+	SetLevel("shared.helper", ErrorLevel)
+}
+
+func TestSetLevelConflictCheck_SetLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	_diagnosticOutput = w
+	SetLevelConflictCheck(true)
+	saved := _levelStore
+	_levelStore = newTestLevelStore()
+	defer func() {
+		_diagnosticOutput = os.Stderr
+		SetLevelConflictCheck(false)
+		_levelStore = saved
+	}()
+	setDebugViaSetLevel()
+	setErrorViaSetLevel()
+	got := w.String()
+	if !strings.Contains(got, `logger "shared.helper" set to ERROR at `) || strings.Contains(got, "level.go") ||
+		strings.Count(got, "conflict_test.go") != 2 {
+		t.Errorf("expect a conflict diagnostic of the call sites, got %q", got)
+	}
+}
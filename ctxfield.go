@@ -0,0 +1,42 @@
+package log
+
+import (
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+var _contextFields = ua.NewUnsafePointer(unsafe.Pointer(&[]Field{}))
+
+// RegisterContextField register a Valuer as a context-derived default field:
+// every Printer got from the builtin Logger carries the field keyed key, whose
+// value is resolved by the Valuer against the context of the Printer at
+// output time, i.e. to add the trace ID or user ID carried by the context.
+// If the key is already registered, the Valuer replaces the existing one. If
+// nil is passed, the key is deregistered.
+func RegisterContextField(key string, v Valuer) {
+	if len(key) == 0 {
+		return
+	}
+	for {
+		old := (*[]Field)(_contextFields.Load())
+		fields := make([]Field, 0, len(*old)+1)
+		for _, field := range *old {
+			if field.Key != key {
+				fields = append(fields, field)
+			}
+		}
+		if v != nil {
+			fields = append(fields, Field{key, v})
+		}
+		if _contextFields.CAS(unsafe.Pointer(old), unsafe.Pointer(&fields)) {
+			break
+		}
+	}
+}
+
+// contextFields returns the registered context-derived default fields, in the
+// order they were registered. The returned slice should not be modified.
+func contextFields() []Field {
+	return *(*[]Field)(_contextFields.Load())
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"unsafe"
+)
+
+type traceIDKey struct{}
+
+func TestRegisterContextField(t *testing.T) {
+	defer _contextFields.Store(unsafe.Pointer(&[]Field{}))
+	calls := 0
+	RegisterContextField("trace_id", func(ctx context.Context) interface{} {
+		calls++
+		return ctx.Value(traceIDKey{})
+	})
+	RegisterContextField("user", func(ctx context.Context) interface{} { return "old" })
+	RegisterContextField("user", func(ctx context.Context) interface{} { return "mike" })
+	RegisterContextField("gone", func(ctx context.Context) interface{} { return "gone" })
+	RegisterContextField("gone", nil)
+	w := &bytes.Buffer{}
+	logger := buildStdLogger("", w)
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "t1")
+	p := logger.AtLevel(ctx, InfoLevel)
+	if calls != 0 {
+		t.Errorf("expect the Valuer not called before output, called %d times", calls)
+	}
+	p.Print("abc")
+	expect := "level=INFO logger= trace_id=t1 user=mike abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger.AtLevel(MDCPut(ctx, "user", "jane"), InfoLevel).Print("abc")
+	expect = "level=INFO logger= trace_id=t1 user=jane abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Describer is implemented by OutPutters which can describe themselves, for
+// diagnosing complex OutPutter chains.
+type Describer interface {
+	// Describe returns a short readable description of the OutPutter itself,
+	// not including the OutPutter it wraps.
+	Describe() string
+}
+
+// Unwrapper is implemented by OutPutters wrapping another OutPutter, i.e.
+// filters.
+type Unwrapper interface {
+	// Unwrap returns the wrapped OutPutter.
+	Unwrap() OutPutter
+}
+
+// Describe returns a readable description of the OutPutter chain starting
+// from o, i.e. `enable -> cover(password) -> std`. It walks the chain through
+// Unwrapper, describing each OutPutter via Describer or its type name.
+func Describe(o OutPutter) string {
+	var parts []string
+	for o != nil {
+		if d, ok := o.(Describer); ok {
+			parts = append(parts, d.Describe())
+		} else {
+			parts = append(parts, fmt.Sprintf("%T", o))
+		}
+		u, ok := o.(Unwrapper)
+		if !ok {
+			break
+		}
+		o = u.Unwrap()
+	}
+	return strings.Join(parts, " -> ")
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+type plainOutPutter struct{}
+
+func (plainOutPutter) OutPut(_ context.Context, _ string, _ Level, _ string, _ []Field, _ int) {
+}
+
+func TestDescribe(t *testing.T) {
+	o := NewStdOutPutter(nil)
+	o = FilterCoverField(o, "password", "***")
+	o = FilterRemoveField(o, "token")
+	o = FilterAddFields(o, Field{"dc", "dc1"}, Field{"app", "demo"})
+	o = FilterWithSampler(o, NewRandomSampler(1))
+	o = FilterEnable(o, func(ctx context.Context, name string, level Level) bool {
+		return level >= WarnLevel
+	})
+	expect := "enable -> sample -> add(dc,app) -> remove(token) -> cover(password) -> std"
+	if got := Describe(o); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	o = FilterWhen(plainOutPutter{}, func(ctx context.Context) bool { return true }, &OutPutFilter{})
+	expect = "when(filter | log.plainOutPutter)"
+	if got := Describe(o); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if got := Describe(nil); got != "" {
+		t.Errorf("expect empty, got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+var (
+	_internalErrorHandler = ua.NewUnsafePointer(unsafe.Pointer((*func(error))(nil)))
+	_reportedErrorTypes   = &sync.Map{}
+)
+
+// SetInternalErrorHandler register a handler which is called when the
+// components of this package can't deliver a log, i.e. on write failures.
+// By default, or if nil is passed, errors are written to the standard error,
+// once per error type.
+// If this function is called more than once, the last call wins.
+func SetInternalErrorHandler(handler func(err error)) {
+	_internalErrorHandler.Store(unsafe.Pointer(&handler))
+}
+
+// handleInternalError passes err to the registered internal error handler.
+func handleInternalError(err error) {
+	if err == nil {
+		return
+	}
+	handler := (*func(error))(_internalErrorHandler.Load())
+	if handler == nil || *handler == nil {
+		defaultInternalErrorHandler(err)
+		return
+	}
+	(*handler)(err)
+}
+
+// defaultInternalErrorHandler writes err to the standard error, once per
+// error type.
+func defaultInternalErrorHandler(err error) {
+	if _, reported := _reportedErrorTypes.LoadOrStore(reflect.TypeOf(err), struct{}{}); reported {
+		return
+	}
+	_, _ = fmt.Fprintf(_diagnosticOutput, "log: internal error: %v\n", err)
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"testing"
+)
+
+type failWriter struct {
+	err error
+}
+
+func (w *failWriter) Write(_ []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestSetInternalErrorHandler(t *testing.T) {
+	defer SetInternalErrorHandler(nil)
+	var got []error
+	SetInternalErrorHandler(func(err error) {
+		got = append(got, err)
+	})
+	writeErr := errors.New("disk full")
+	o := NewStdOutPutter(log.New(&failWriter{writeErr}, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+	if len(got) != 1 || got[0] != writeErr {
+		t.Errorf("expect handler fired with %v, got %v", writeErr, got)
+	}
+}
+
+func TestDefaultInternalErrorHandler(t *testing.T) {
+	w := &bytes.Buffer{}
+	_diagnosticOutput = w
+	_reportedErrorTypes = &sync.Map{}
+	defer func() {
+		_diagnosticOutput = os.Stderr
+	}()
+	SetInternalErrorHandler(nil)
+	handleInternalError(nil)
+	handleInternalError(errors.New("disk full"))
+	handleInternalError(errors.New("disk full again"))
+	expect := "log: internal error: disk full\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
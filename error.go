@@ -0,0 +1,56 @@
+package log
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError is an error that contributes its own structured fields when
+// logged.
+type FieldError interface {
+	error
+	// LogFields returns the fields describing the error.
+	LogFields() []Field
+}
+
+// errorFieldPrefix is the key prefix of the fields collected from errors.
+const errorFieldPrefix = "error."
+
+// ErrorFields walks the unwrap chain of err and collects the fields of every
+// FieldError in the chain, from the outermost to the innermost. The keys of
+// the collected fields are prefixed with `error.`.
+func ErrorFields(err error) []Field {
+	var fields []Field
+	for ; err != nil; err = errors.Unwrap(err) {
+		fe, ok := err.(FieldError)
+		if !ok {
+			continue
+		}
+		for _, field := range fe.LogFields() {
+			if len(field.Key) == 0 {
+				continue
+			}
+			fields = append(fields, Field{errorFieldPrefix + field.Key, field.Value})
+		}
+	}
+	return fields
+}
+
+// errorList is an error aggregating multiple errors.
+type errorList []error
+
+func (l errorList) Error() string {
+	msgs := make([]string, 0, len(l))
+	for _, err := range l {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// err returns the errorList as an error, or nil if it is empty.
+func (l errorList) err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
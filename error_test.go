@@ -0,0 +1,54 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type queryError struct {
+	table string
+	err   error
+}
+
+func (e *queryError) Error() string {
+	return "query " + e.table + ": " + e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+func (e *queryError) LogFields() []Field {
+	return []Field{{"table", e.table}}
+}
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return fmt.Sprintf("code %d", e.code)
+}
+
+func (e *codeError) LogFields() []Field {
+	return []Field{{"code", e.code}, {"", "ignored"}}
+}
+
+func TestErrorFields(t *testing.T) {
+	err := fmt.Errorf("find user: %w", &queryError{"users", &codeError{1045}})
+	expect := []Field{
+		{"error.table", "users"},
+		{"error.code", 1045},
+	}
+	if got := ErrorFields(err); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %v, got %v", expect, got)
+	}
+	if got := ErrorFields(errors.New("plain")); got != nil {
+		t.Errorf("expect nil, got %v", got)
+	}
+	if got := ErrorFields(nil); got != nil {
+		t.Errorf("expect nil, got %v", got)
+	}
+}
@@ -0,0 +1,30 @@
+package log
+
+import (
+	"os"
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+var _exitFunc = ua.NewUnsafePointer(unsafe.Pointer((*func(code int))(nil)))
+
+// SetExitFunc register the function the builtin Printers call to exit the
+// process after printing at FatalLevel. It is replaceable for testing. By
+// default, or if nil is passed, os.Exit is used.
+// Like log.Fatal, printing at FatalLevel always exits, even if FatalLevel is
+// not enabled, in which case nothing is printed. Before exiting, Shutdown is
+// called with a bounded timeout, so buffering OutPutters flush the record.
+// If this function is called more than once, the last call wins.
+func SetExitFunc(fn func(code int)) {
+	_exitFunc.Store(unsafe.Pointer(&fn))
+}
+
+// exit exits the process with the code via the registered exit function.
+func exit(code int) {
+	fn := (*func(code int))(_exitFunc.Load())
+	if fn == nil || *fn == nil {
+		os.Exit(code)
+	}
+	(*fn)(code)
+}
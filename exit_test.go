@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestSetExitFunc(t *testing.T) {
+	defer SetExitFunc(nil)
+	w := &bytes.Buffer{}
+	var codes []int
+	SetExitFunc(func(code int) {
+		if expect := "level=FATAL logger=exit abc\n"; w.String() != expect {
+			t.Errorf("expect %q printed before exit, got %q", expect, w.String())
+		}
+		codes = append(codes, code)
+	})
+	logger := NewStdLogger("exit", NewStdOutPutter(log.New(w, "", 0)))
+	logger.AtLevel(context.Background(), ErrorLevel).Print("abc")
+	if len(codes) != 0 {
+		t.Errorf("expect no exit at ErrorLevel, got %v", codes)
+	}
+	w.Reset()
+	logger.AtLevel(context.Background(), FatalLevel).Print("abc")
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Errorf("expect exit with code 1, got %v", codes)
+	}
+}
+
+func TestSetExitFunc_Shutdown(t *testing.T) {
+	defer SetExitFunc(nil)
+	w := &bytes.Buffer{}
+	o, closer := NewBufferedOutPutter(log.New(w, "", 0), 1<<20, time.Hour)
+	defer closer.Close()
+	var printed string
+	SetExitFunc(func(code int) {
+		printed = w.String()
+	})
+	logger := NewStdLogger("exit", o)
+	logger.AtLevel(context.Background(), FatalLevel).Print("abc")
+	if expect := "level=FATAL logger=exit abc\n"; printed != expect {
+		t.Errorf("expect %q flushed before exit, got %q", expect, printed)
+	}
+}
+
+func TestSetExitFunc_Disabled(t *testing.T) {
+	defer SetExitFunc(nil)
+	w := &bytes.Buffer{}
+	var codes []int
+	SetExitFunc(func(code int) {
+		codes = append(codes, code)
+	})
+	store := NewLevelStore(map[string]Level{"exit": ClosedLevel})
+	logger := NewStdLogger("exit", NewStdOutPutter(log.New(w, "", 0)), WithLevelStore(store))
+	p, ok := logger.TryAtLevel(context.Background(), FatalLevel)
+	if ok {
+		t.Errorf("expect FatalLevel disabled")
+	}
+	p.With("k", "v").Print("abc")
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Errorf("expect exit with code 1, got %v", codes)
+	}
+	if w.Len() != 0 {
+		t.Errorf("expect nothing printed, got %q", w.String())
+	}
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// flagValue is the value type of Field built by Flag. A flag field is
+// rendered as a bare key by text OutPutters.
+type flagValue struct{}
+
+func (flagValue) String() string {
+	return "true"
+}
+
+func (flagValue) MarshalJSON() ([]byte, error) {
+	return []byte("true"), nil
+}
+
+// Flag build a Field with flag semantics: its presence means true. Text
+// OutPutters render it as a bare key, JSON ones as `"key": true`.
+// There is no false flag, a false flag field should just not be added.
+func Flag(key string) Field {
+	return Field{Key: key, Value: flagValue{}}
+}
+
+// fieldValues is the value type of a Field whose values are accumulated by
+// Printer.Append. Text OutPutters render it as a comma separated list.
+type fieldValues []interface{}
+
+// Int build a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 build a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Uint64 build a Field with an uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 build a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// enumValue is the value type of Field built by Enum.
+type enumValue struct {
+	name    fmt.Stringer
+	numeric interface{}
+}
+
+func (e enumValue) String() string {
+	return fmt.Sprintf("%s(%v)", e.name, e.numeric)
+}
+
+// MarshalJSON renders the name like String, so a nil name is not called.
+func (e enumValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	}{fmt.Sprintf("%s", e.name), e.numeric})
+}
+
+// Enum build a Field holding an enum-like value with both its name and its
+// underlying numeric value, i.e. a protocol code or a status. Text OutPutters
+// render it as `key=NAME(num)`, JSON ones as `"key": {"name": NAME, "value": num}`.
+func Enum(key string, value fmt.Stringer, numeric interface{}) Field {
+	return Field{Key: key, Value: enumValue{name: value, numeric: numeric}}
+}
+
+// LogValuer is implemented by types controlling their log representation.
+// See Printer.WithValue. An error implementing it is a FieldError, and is not
+// taken as a LogValuer by WithValue, see Printer.WithError instead.
+type LogValuer interface {
+	// LogFields returns the fields representing the value.
+	LogFields() []Field
+}
+
+// maxLogValueDepth is the depth LogValuers nested in the fields of another are
+// expanded to. Values nested deeper are added as they are.
+const maxLogValueDepth = 8
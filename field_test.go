@@ -0,0 +1,121 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"testing"
+)
+
+func TestFlag(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{Flag("present")}, 0)
+	expect := "present abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	bs, err := json.Marshal(map[string]interface{}{"present": Flag("present").Value})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(bs) != `{"present":true}` {
+		t.Errorf("expect %q, got %q", `{"present":true}`, string(bs))
+	}
+}
+
+type testStatus int
+
+func (s testStatus) String() string {
+	return [...]string{"PENDING", "DONE"}[s]
+}
+
+func TestEnum(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{Enum("status", testStatus(1), 1)}, 0)
+	expect := "status=DONE(1) abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	bs, err := json.Marshal(map[string]interface{}{"status": Enum("status", testStatus(0), 0).Value})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"status":{"name":"PENDING","value":0}}`; string(bs) != expect {
+		t.Errorf("expect %q, got %q", expect, string(bs))
+	}
+	bs, err = json.Marshal(Enum("status", nil, 2).Value)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expect := `{"name":"%!s(\u003cnil\u003e)","value":2}`; string(bs) != expect {
+		t.Errorf("expect %q, got %q", expect, string(bs))
+	}
+}
+
+func TestNumericFields(t *testing.T) {
+	fields := []Field{
+		Int("int", -42),
+		Int64("int64", math.MinInt64),
+		Uint64("uint64", math.MaxUint64),
+		Float64("f1", 3.14),
+		Float64("f2", 1e21),
+		Float64("f3", 1e-7),
+		Float64("f4", 100000000),
+		Float64("f5", math.Inf(1)),
+		Float64("f6", math.NaN()),
+	}
+	s := NewStdOutPutter(nil).(*stdOutPutter)
+	for _, field := range fields {
+		buf := &bytes.Buffer{}
+		s.writeValue(context.Background(), buf, field.Value)
+		if expect := fmt.Sprintf("%v", field.Value); buf.String() != expect {
+			t.Errorf("%s: expect %q, got %q", field.Key, expect, buf.String())
+		}
+	}
+}
+
+func TestNumericFields_Allocs(t *testing.T) {
+	s := NewStdOutPutter(nil).(*stdOutPutter)
+	buf := &bytes.Buffer{}
+	buf.Grow(1024)
+	values := []interface{}{42, int64(42), uint64(42), 4.2}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		for _, v := range values {
+			s.writeValue(context.Background(), buf, v)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expect no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkNumericFields_Typed(b *testing.B) {
+	s := NewStdOutPutter(nil).(*stdOutPutter)
+	buf := &bytes.Buffer{}
+	values := []interface{}{42, int64(42), uint64(42), 4.2}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		for _, v := range values {
+			s.writeValue(context.Background(), buf, v)
+		}
+	}
+}
+
+func BenchmarkNumericFields_Fmt(b *testing.B) {
+	buf := &bytes.Buffer{}
+	values := []interface{}{42, int64(42), uint64(42), 4.2}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		for _, v := range values {
+			_, _ = fmt.Fprintf(buf, "%v", v)
+		}
+	}
+}
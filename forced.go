@@ -0,0 +1,28 @@
+package log
+
+import "context"
+
+// forcedLevelKey is the context key of the forced Level.
+type forcedLevelKey struct{}
+
+// WithForcedLevel returns a copy of ctx carrying a forced Level. The builtin
+// Loggers use the forced Level, instead of the Level set in the LevelStore, as
+// the lowest Level they enable for Printers got with the returned context (or
+// its children). It takes precedence over the LevelStore in both directions:
+// it can make a call chain more verbose, i.e. debugging a single request by
+// injecting the override at the edge, as well as quieter.
+func WithForcedLevel(ctx context.Context, level Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, forcedLevelKey{}, level)
+}
+
+// forcedLevel returns the forced Level carried by ctx, and whether it exists.
+func forcedLevel(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(forcedLevelKey{}).(Level)
+	return level, ok
+}
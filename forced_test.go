@@ -0,0 +1,27 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestWithForcedLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	store := GetLevelStore()
+	store.Set("forced", WarnLevel)
+	defer store.UnSet("forced")
+	logger := buildStdLogger("forced", w)
+	ctx := WithForcedLevel(context.Background(), DebugLevel)
+	logger.AtLevel(ctx, DebugLevel).Print("abc")
+	expect := "level=DEBUG logger=forced abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if logger.Enabled(context.Background(), DebugLevel) {
+		t.Errorf("expect DebugLevel not enabled without forced level, but enabled")
+	}
+	if logger.Enabled(WithForcedLevel(ctx, ErrorLevel), WarnLevel) {
+		t.Errorf("expect WarnLevel not enabled with forced ErrorLevel, but enabled")
+	}
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFramedOutPutter_RoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	o := NewFramedOutPutter(buf)
+	ctx := context.Background()
+	o.OutPut(ctx, "pkg", WarnLevel, "disk almost full", []Field{
+		{LevelKey, WarnLevel},
+		{"", "skipped"},
+		{"free", Valuer(func(ctx context.Context) interface{} { return 42 })},
+	}, 0)
+	o.OutPut(ctx, "", TraceLevel, "", nil, 0)
+	expects := []Record{
+		{Name: "pkg", Level: WarnLevel, Msg: "disk almost full", Fields: []Field{
+			{LevelKey, "WARN"},
+			{"free", "42"},
+		}},
+		{Name: "", Level: TraceLevel, Msg: "", Fields: []Field{}},
+	}
+	for _, expect := range expects {
+		got, err := ReadFrame(buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("expect %+v, got %+v", expect, got)
+		}
+	}
+	if _, err := ReadFrame(buf); err != io.EOF {
+		t.Errorf("expect io.EOF, got %v", err)
+	}
+}
+
+func TestReadFrame_Malformed(t *testing.T) {
+	frames := [][]byte{
+		{0x05, 0x00, 0x01},
+		{0x00},
+		{0x03, 0x00, 0x05, 'a'},
+		{0x04, 0x00, 0x00, 0x00, 0x01},
+		{0xff, 0xff, 0xff, 0xff, 0x7f},
+	}
+	for _, frame := range frames {
+		if _, err := ReadFrame(bytes.NewReader(frame)); err != ErrMalformedFrame {
+			t.Errorf("%v: expect ErrMalformedFrame, got %v", frame, err)
+		}
+	}
+}
+
+func BenchmarkFramedOutPutter(b *testing.B) {
+	o := NewFramedOutPutter(io.Discard)
+	fields := []Field{{LevelKey, InfoLevel}, {LoggerKey, "pkg"}, {"k", "v"}}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		o.OutPut(ctx, "pkg", InfoLevel, "abc", fields, 0)
+	}
+}
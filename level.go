@@ -3,6 +3,9 @@ package log
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"unsafe"
 
 	ua "go.uber.org/atomic"
@@ -13,9 +16,13 @@ type Level int8
 
 // Define logging Levels. Default level is InfoLevel.
 const (
+	// TraceLevel logs are the most verbose diagnostics, even more voluminous
+	// than DebugLevel. It is the lowest predefined level, so enabling
+	// TraceLevel enables everything.
+	TraceLevel Level = iota - 2
 	// DebugLevel logs are typically voluminous, and are usually disabled in
 	// production.
-	DebugLevel Level = iota - 1
+	DebugLevel
 	// InfoLevel is the default logging priority.
 	InfoLevel
 	// WarnLevel logs are more important than Info, but don't need individual
@@ -24,15 +31,24 @@ const (
 	// ErrorLevel logs are high-priority. If an application is running smoothly,
 	// it shouldn't generate any error-level logs.
 	ErrorLevel
+	// PanicLevel logs are violations of invariants. The builtin Printers
+	// panic with the message after printing.
+	PanicLevel
+	// FatalLevel logs are unrecoverable errors. The builtin Printers exit the
+	// process via the exit function (see SetExitFunc) after printing.
+	FatalLevel
 	// ClosedLevel logs output nothing.
 	ClosedLevel = math.MaxInt8
 )
 
 var _levelNames = ua.NewUnsafePointer(unsafe.Pointer(&map[Level]string{
+	TraceLevel: "TRACE",
 	DebugLevel: "DEBUG",
 	InfoLevel:  "INFO",
 	WarnLevel:  "WARN",
 	ErrorLevel: "ERROR",
+	PanicLevel: "PANIC",
+	FatalLevel: "FATAL",
 }))
 
 // RegisterLevelName register the name of one level. If the level is already exists,
@@ -52,12 +68,83 @@ func RegisterLevelName(level Level, name string) {
 	}
 }
 
-// String returns a lower-case ASCII representation of the log level.
-func (l Level) String() string {
+// levelName returns the registered name of the level, and whether it is
+// registered.
+func levelName(l Level) (string, bool) {
 	load := (*map[Level]string)(_levelNames.Load())
 	name := (*load)[l]
-	if len(name) == 0 {
+	return name, len(name) != 0
+}
+
+// String returns a lower-case ASCII representation of the log level.
+func (l Level) String() string {
+	name, ok := levelName(l)
+	if !ok {
 		return fmt.Sprintf("Level(%d)", l)
 	}
 	return name
 }
+
+// ParseLevel parses a Level from its name, case-insensitively. It is the
+// inverse of Level.String: the names registered by RegisterLevelName, and the
+// `Level(N)` form of levels without names are recognized. An exact match of
+// a name wins; otherwise, if names differing only by case match, the lowest
+// Level wins. An error is returned if s is not recognized.
+func ParseLevel(s string) (Level, error) {
+	names := *(*map[Level]string)(_levelNames.Load())
+	levels := make([]Level, 0, len(names))
+	for level, name := range names {
+		if name == s {
+			return level, nil
+		}
+		if len(name) != 0 && strings.EqualFold(name, s) {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) > 0 {
+		sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+		return levels[0], nil
+	}
+	if len(s) > len("Level()") && strings.EqualFold(s[:len("Level(")], "Level(") && s[len(s)-1] == ')' {
+		n, err := strconv.ParseInt(s[len("Level("):len(s)-1], 10, 8)
+		if err == nil {
+			return Level(n), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown level %q", s)
+}
+
+// SetRootLevel set the level of the root (named by an empty string) in the
+// LevelStore returned by GetLevelStore, which applies to every Logger without
+// a level set for itself or its ancestors.
+func SetRootLevel(level Level) {
+	setLevel("", level, 1)
+}
+
+// RootLevel returns the level of the root in the LevelStore returned by
+// GetLevelStore. InfoLevel is returned if there is no LevelStore.
+func RootLevel() Level {
+	store := GetLevelStore()
+	if store == nil {
+		return InfoLevel
+	}
+	return store.Get("")
+}
+
+// SetLevel set the level of the Logger by name in the LevelStore returned by
+// GetLevelStore. It is a shortcut of `GetLevelStore().Set(name, level)`.
+func SetLevel(name string, level Level) {
+	setLevel(name, level, 1)
+}
+
+// setLevel is SetLevel reporting the call site skip frames above the caller
+// of setLevel to the level conflict check (see SetLevelConflictCheck).
+func setLevel(name string, level Level, skip int) {
+	switch store := GetLevelStore().(type) {
+	case nil:
+	case *stdLevelStore:
+		store.set(name, level, skip+1)
+	default:
+		store.Set(name, level)
+	}
+}
@@ -7,10 +7,13 @@ import (
 
 func resetLevelNames() {
 	_levelNames.Swap(unsafe.Pointer(&map[Level]string{
+		TraceLevel: "TRACE",
 		DebugLevel: "DEBUG",
 		InfoLevel:  "INFO",
 		WarnLevel:  "WARN",
 		ErrorLevel: "ERROR",
+		PanicLevel: "PANIC",
+		FatalLevel: "FATAL",
 	}))
 }
 
@@ -36,6 +39,9 @@ func TestLevel_String(t *testing.T) {
 	if InfoLevel.String() != "INFO" {
 		t.Errorf("string of InfoLevel is not INFO!")
 	}
+	if TraceLevel.String() != "TRACE" {
+		t.Errorf("string of TraceLevel is not TRACE!")
+	}
 	if Level(99).String() != "Level(99)" {
 		t.Errorf("string of unregister Level(99) should be \"Level(99)\", but is %s",
 			Level(99).String())
@@ -51,3 +57,77 @@ func TestLevel_String(t *testing.T) {
 			Level(99).String())
 	}
 }
+
+func TestParseLevel(t *testing.T) {
+	defer resetLevelNames()
+	RegisterLevelName(Level(5), "NOTICE")
+	tests := map[string]Level{
+		"INFO":      InfoLevel,
+		"debug":     DebugLevel,
+		"Trace":     TraceLevel,
+		"notice":    Level(5),
+		"Level(7)":  Level(7),
+		"level(-5)": Level(-5),
+	}
+	for s, expect := range tests {
+		got, err := ParseLevel(s)
+		if err != nil || got != expect {
+			t.Errorf("%s: expect %v, got %v, %v", s, expect, got, err)
+		}
+	}
+	for _, s := range []string{"", "verbose", "INFO ", "Level()", "Level(x)", "Level(128)", "Level(1"} {
+		if _, err := ParseLevel(s); err == nil {
+			t.Errorf("%q: expect error, got nil", s)
+		}
+	}
+}
+
+func TestParseLevel_CaseConflict(t *testing.T) {
+	defer resetLevelNames()
+	RegisterLevelName(Level(5), "Notice")
+	RegisterLevelName(Level(6), "NOTICE")
+	RegisterLevelName(Level(7), "notice")
+	for i := 0; i < 20; i++ {
+		tests := map[string]Level{
+			"Notice": Level(5),
+			"NOTICE": Level(6),
+			"notice": Level(7),
+			"nOTICE": Level(5),
+		}
+		for s, expect := range tests {
+			if got, err := ParseLevel(s); err != nil || got != expect {
+				t.Fatalf("%s: expect %v, got %v, %v", s, expect, got, err)
+			}
+		}
+	}
+}
+
+func TestParseLevel_RoundTrip(t *testing.T) {
+	defer resetLevelNames()
+	RegisterLevelName(Level(5), "NOTICE")
+	for _, level := range []Level{TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, Level(5), Level(-100), ClosedLevel} {
+		got, err := ParseLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("%v: expect round-trip, got %v, %v", level, got, err)
+		}
+	}
+}
+
+func TestSetRootLevel(t *testing.T) {
+	defer SetRootLevel(InfoLevel)
+	SetRootLevel(WarnLevel)
+	if got := RootLevel(); got != WarnLevel {
+		t.Errorf("expect %v, got %v", WarnLevel, got)
+	}
+	if got := GetLevelStore().Get("xyz"); got != WarnLevel {
+		t.Errorf("expect %v, got %v", WarnLevel, got)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	defer GetLevelStore().UnSet("pkg")
+	SetLevel("pkg", DebugLevel)
+	if got := GetLevelStore().Get("pkg/sub"); got != DebugLevel {
+		t.Errorf("expect %v, got %v", DebugLevel, got)
+	}
+}
@@ -3,6 +3,8 @@ package log
 import (
 	"context"
 	"log"
+	"runtime"
+	"strings"
 	"unsafe"
 
 	ua "go.uber.org/atomic"
@@ -39,6 +41,39 @@ type Printer interface {
 	// please refer the manual of the implementation) may should be avoid, since
 	// the caller key/value pair may be added by the implementation.
 	With(key string, value interface{}) Printer
+	// WithFields add each field to the Printer in order, just like calling
+	// With with its key and value.
+	WithFields(fields ...Field) Printer
+	// WithError add the error to the Printer as the ErrorFieldKey field,
+	// together with the fields carried by the error chain (see ErrorFields).
+	// The error value itself is stored, not its message. Nothing is added if
+	// err is nil.
+	WithError(err error) Printer
+	// WithFlag add a flag Field (see Flag) with the key to the Printer only if
+	// cond is true. Nothing is added if cond is false.
+	WithFlag(key string, cond bool) Printer
+	// Append add key/value pair to the Printer. Unlike With, if the key is
+	// already exist, the existing value and the given value are combined into
+	// a list of values rather than being overridden.
+	Append(key string, value interface{}) Printer
+	// Count add the metric/delta pair to the Printer as a field, and increment
+	// the metric by delta via the registered MetricsHook when the line is
+	// emitted. See SetMetricsHook.
+	Count(metric string, delta int64) Printer
+	// Event add the event name to the Printer as the EventKey field. An event
+	// name is a stable identifier of what happened, i.e. `user.login`,
+	// separated from the human readable message, so that logs can be queried
+	// by a taxonomy of events.
+	Event(name string) Printer
+	// WithLabels add each label as a key/value pair to the Printer, with the
+	// key prefixed (`label.` by default, depending on the implementation).
+	// Labels are added in the sorted order of their names.
+	WithLabels(labels map[string]string) Printer
+	// WithValue add key/value pair to the Printer like With, unless the value
+	// is a LogValuer but not an error: then each of its fields is added with
+	// the key prefixed by `key.`, so that the value controls its log
+	// representation.
+	WithValue(key string, value interface{}) Printer
 }
 
 // nopPrinter is a Printer that print nothing.
@@ -63,6 +98,38 @@ func (p *nopPrinter) With(_ string, _ interface{}) Printer {
 	return p
 }
 
+func (p *nopPrinter) WithFields(_ ...Field) Printer {
+	return p
+}
+
+func (p *nopPrinter) WithError(_ error) Printer {
+	return p
+}
+
+func (p *nopPrinter) WithFlag(_ string, _ bool) Printer {
+	return p
+}
+
+func (p *nopPrinter) Append(_ string, _ interface{}) Printer {
+	return p
+}
+
+func (p *nopPrinter) Count(_ string, _ int64) Printer {
+	return p
+}
+
+func (p *nopPrinter) Event(_ string) Printer {
+	return p
+}
+
+func (p *nopPrinter) WithLabels(_ map[string]string) Printer {
+	return p
+}
+
+func (p *nopPrinter) WithValue(_ string, _ interface{}) Printer {
+	return p
+}
+
 // Logger represents a logger that can provide Printers. A Logger has a name,
 // and there may be a lower limit of logging Level the Logger supported.
 type Logger interface {
@@ -71,6 +138,28 @@ type Logger interface {
 	// print when calling the Print functions of returned Printer.
 	// If the context.Context provided is nil, context.Background() will be used.
 	AtLevel(ctx context.Context, level Level) Printer
+	// Enabled reports whether the Level is enabled to the Logger, so that
+	// callers can skip building expensive arguments of disabled logs.
+	Enabled(ctx context.Context, level Level) bool
+	// TryAtLevel is like AtLevel, but also reports whether the Level is
+	// enabled to the Logger, so that callers can branch on it without
+	// resolving the Level twice. If not enabled, a Printer printing nothing
+	// and false are returned.
+	TryAtLevel(ctx context.Context, level Level) (Printer, bool)
+	// AtLevels get a Printer which prints once per each of the levels, like
+	// calling AtLevel with each level in order. Each print carries its own
+	// level. Levels not enabled to the Logger are skipped.
+	// It is mainly a migration aid for verifying a change of the level of a
+	// log line.
+	AtLevels(ctx context.Context, levels ...Level) Printer
+	// V get a Printer at the Level of the numeric verbosity v (see
+	// VerbosityLevel), like glog/klog style verbose logging. It is enabled only
+	// if the verbosity set for the Logger (see SetVerbosity) is v or greater.
+	V(ctx context.Context, v int) Printer
+	// Named get a child Logger named by the name of the Logger and the suffix
+	// joined with `.`, so that the child follows the levels of the Logger in
+	// the LevelStore hierarchy. An empty suffix returns the Logger itself.
+	Named(suffix string) Logger
 }
 
 // LoggerProvider is provider function that provide a non-nil Logger by name.
@@ -97,3 +186,35 @@ func Get(name string) Logger {
 	}
 	return (*(*LoggerProvider)(_loggerProvider.Load()))(name)
 }
+
+// GetAuto return a Logger named by the package path of the caller, i.e.
+// `Get("foo/bar")` if called in package foo/bar. It is expected to be called
+// once per package, i.e. at init, for the cost of finding the caller.
+func GetAuto() Logger {
+	return Get(callerPackage(1))
+}
+
+// callerPackage returns the package path of the function skip frames above
+// the caller of callerPackage, or an empty string if it is unknown.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	return packageOf(fn.Name())
+}
+
+// packageOf returns the package path of a fully qualified function name, i.e.
+// `foo/bar` of `foo/bar.(*T).Method`. Dots in the last element of the path
+// are escaped as `%2e` in function names.
+func packageOf(funcName string) string {
+	slash := strings.LastIndexByte(funcName, '/') + 1
+	if dot := strings.IndexByte(funcName[slash:], '.'); dot >= 0 {
+		funcName = funcName[:slash+dot]
+	}
+	return strings.ReplaceAll(funcName, "%2e", ".")
+}
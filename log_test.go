@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -25,6 +26,46 @@ func TestNopPrinter_With(t *testing.T) {
 	_ = p.With("key", "value")
 }
 
+func TestNopPrinter_WithFields(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.WithFields(Field{"a", 1}, Field{"b", 2})
+}
+
+func TestNopPrinter_WithError(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.WithError(errors.New("abc"))
+}
+
+func TestNopPrinter_WithFlag(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.WithFlag("key", true)
+}
+
+func TestNopPrinter_Append(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.Append("key", "value")
+}
+
+func TestNopPrinter_Count(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.Count("metric", 1)
+}
+
+func TestNopPrinter_Event(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.Event("user.login")
+}
+
+func TestNopPrinter_WithLabels(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.WithLabels(map[string]string{"app": "demo"})
+}
+
+func TestNopPrinter_WithValue(t *testing.T) {
+	p := NewNopPrinter()
+	_ = p.WithValue("key", "value")
+}
+
 type nopLogger struct {
 }
 
@@ -34,6 +75,26 @@ func (l *nopLogger) AtLevel(_ context.Context, _ Level) Printer {
 	return NewNopPrinter()
 }
 
+func (l *nopLogger) Enabled(_ context.Context, _ Level) bool {
+	return false
+}
+
+func (l *nopLogger) TryAtLevel(_ context.Context, _ Level) (Printer, bool) {
+	return NewNopPrinter(), false
+}
+
+func (l *nopLogger) AtLevels(_ context.Context, _ ...Level) Printer {
+	return NewNopPrinter()
+}
+
+func (l *nopLogger) V(_ context.Context, _ int) Printer {
+	return NewNopPrinter()
+}
+
+func (l *nopLogger) Named(_ string) Logger {
+	return l
+}
+
 func nopProvider(_ string) Logger {
 	return &nopLogger{}
 }
@@ -76,3 +137,26 @@ func TestGet(t *testing.T) {
 		t.Errorf("expect not nil, but got nil")
 	}
 }
+
+func TestGetAuto(t *testing.T) {
+	logger := GetAuto()
+	if name := logger.(*stdLogger).name; name != "github.com/go-kita/log" {
+		t.Errorf("expect %q, got %q", "github.com/go-kita/log", name)
+	}
+}
+
+func TestPackageOf(t *testing.T) {
+	tests := map[string]string{
+		"github.com/go-kita/log.TestGetAuto":        "github.com/go-kita/log",
+		"github.com/go-kita/log.(*stdLogger).Named": "github.com/go-kita/log",
+		"foo/bar.init.0":                            "foo/bar",
+		"foo/bar%2ev2.Func.func1":                   "foo/bar.v2",
+		"main.main":                                 "main",
+		"gopkg.in/yaml%2ev2.Unmarshal":              "gopkg.in/yaml.v2",
+	}
+	for funcName, expect := range tests {
+		if got := packageOf(funcName); got != expect {
+			t.Errorf("%s: expect %q, got %q", funcName, expect, got)
+		}
+	}
+}
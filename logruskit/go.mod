@@ -0,0 +1,16 @@
+module github.com/go-kita/log/logruskit
+
+go 1.18
+
+require (
+	github.com/go-kita/log v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require (
+	github.com/stretchr/testify v1.8.4 // indirect
+	go.uber.org/atomic v1.8.0 // indirect
+	golang.org/x/sys v0.9.0 // indirect
+)
+
+replace github.com/go-kita/log => ../
@@ -0,0 +1,85 @@
+// Package logruskit provides a LoggerProvider writing logs through logrus.
+// It is a separated module so that the logrus dependency is kept out of the
+// core logging module.
+package logruskit
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-kita/log"
+)
+
+// outPutter is an OutPutter writing records as logrus entries.
+type outPutter struct {
+	logger *logrus.Logger
+}
+
+// NewLogrusLoggerProvider create a LoggerProvider whose Loggers write records
+// through the provided logrus.Logger, so that an existing logrus configuration
+// backs this package, i.e. with UseProvider. The Loggers are the builtin ones,
+// gated by the LevelStore by name; the records are gated by the level of the
+// logrus.Logger too. Levels are mapped by LogrusLevel.
+// Each record is a logrus entry with the fields as logrus.Fields, Valuers
+// resolved, and the logger name as the field keyed LoggerKey. A PanicLevel
+// record is logged without logrus panicking, which is done by the Printer
+// after printing.
+func NewLogrusLoggerProvider(l *logrus.Logger, opts ...log.StdLoggerOption) log.LoggerProvider {
+	return log.NewStdLoggerProvider(&outPutter{logger: l}, opts...)
+}
+
+// LogrusLevel maps a Level to a logrus.Level: levels up to TraceLevel are
+// logrus.TraceLevel, DebugLevel to PanicLevel are the logrus levels of the
+// same names, and the higher are logrus.FatalLevel.
+func LogrusLevel(level log.Level) logrus.Level {
+	switch {
+	case level <= log.TraceLevel:
+		return logrus.TraceLevel
+	case level == log.DebugLevel:
+		return logrus.DebugLevel
+	case level == log.InfoLevel:
+		return logrus.InfoLevel
+	case level == log.WarnLevel:
+		return logrus.WarnLevel
+	case level == log.ErrorLevel:
+		return logrus.ErrorLevel
+	case level == log.PanicLevel:
+		return logrus.PanicLevel
+	default:
+		return logrus.FatalLevel
+	}
+}
+
+func (o *outPutter) OutPut(
+	ctx context.Context, name string, level log.Level, msg string, fields []log.Field, _ int) {
+	ll := LogrusLevel(level)
+	if !o.logger.IsLevelEnabled(ll) {
+		return
+	}
+	data := make(logrus.Fields, len(fields))
+	for _, field := range fields {
+		if len(field.Key) == 0 || field.Key == log.LevelKey {
+			continue
+		}
+		data[field.Key] = log.NamedValue(ctx, name, field.Value)
+	}
+	data[log.LoggerKey] = name
+	entry := o.logger.WithContext(ctx).WithFields(data)
+	if ll == logrus.PanicLevel {
+		// logrus panics with the entry after logging, other panics, i.e. of
+		// hooks, are propagated
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(*logrus.Entry); !ok {
+					panic(r)
+				}
+			}
+		}()
+	}
+	entry.Log(ll, msg)
+}
+
+func (o *outPutter) Describe() string {
+	return "logrus"
+}
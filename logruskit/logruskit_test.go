@@ -0,0 +1,92 @@
+package logruskit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/go-kita/log"
+)
+
+func TestNewLogrusLoggerProvider(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	l.SetLevel(logrus.WarnLevel)
+	provider := NewLogrusLoggerProvider(l)
+	logger := provider("app")
+	ctx := context.Background()
+	logger.AtLevel(ctx, log.InfoLevel).Print("hidden")
+	if entries := hook.AllEntries(); len(entries) != 0 {
+		t.Errorf("expect no entry, got %d", len(entries))
+	}
+	user := log.Valuer(func(ctx context.Context) interface{} { return "mike" })
+	logger.AtLevel(ctx, log.ErrorLevel).With("user", user).Print("abc")
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatalf("expect an entry, got nil")
+	}
+	if entry.Level != logrus.ErrorLevel || entry.Message != "abc" {
+		t.Errorf("unexpected entry: %v %q", entry.Level, entry.Message)
+	}
+	expect := logrus.Fields{"logger": "app", "user": "mike"}
+	if !reflect.DeepEqual(entry.Data, expect) {
+		t.Errorf("expect %v, got %v", expect, entry.Data)
+	}
+}
+
+func TestNewLogrusLoggerProvider_PanicLevel(t *testing.T) {
+	l, hook := test.NewNullLogger()
+	logger := NewLogrusLoggerProvider(l)("app")
+	defer func() {
+		if got := recover(); got != "abc" {
+			t.Errorf("expect panic with %q, got %v", "abc", got)
+		}
+		if entry := hook.LastEntry(); entry == nil || entry.Level != logrus.PanicLevel {
+			t.Errorf("expect a PanicLevel entry, got %v", entry)
+		}
+	}()
+	logger.AtLevel(context.Background(), log.PanicLevel).Print("abc")
+}
+
+// panicHook is a logrus.Hook panicking on fire.
+type panicHook struct{}
+
+func (panicHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (panicHook) Fire(_ *logrus.Entry) error {
+	panic("hook failed")
+}
+
+func TestNewLogrusLoggerProvider_HookPanic(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	l.AddHook(panicHook{})
+	logger := NewLogrusLoggerProvider(l)("app")
+	defer func() {
+		if got := recover(); got != "hook failed" {
+			t.Errorf("expect panic with %q, got %v", "hook failed", got)
+		}
+	}()
+	logger.AtLevel(context.Background(), log.PanicLevel).Print("abc")
+}
+
+func TestLogrusLevel(t *testing.T) {
+	tests := map[log.Level]logrus.Level{
+		log.Level(-5):  logrus.TraceLevel,
+		log.TraceLevel: logrus.TraceLevel,
+		log.DebugLevel: logrus.DebugLevel,
+		log.InfoLevel:  logrus.InfoLevel,
+		log.WarnLevel:  logrus.WarnLevel,
+		log.ErrorLevel: logrus.ErrorLevel,
+		log.PanicLevel: logrus.PanicLevel,
+		log.FatalLevel: logrus.FatalLevel,
+	}
+	for level, expect := range tests {
+		if got := LogrusLevel(level); got != expect {
+			t.Errorf("%v: expect %v, got %v", level, expect, got)
+		}
+	}
+}
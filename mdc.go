@@ -0,0 +1,55 @@
+package log
+
+import "context"
+
+// mdcKey is the context key of the mapped diagnostic context.
+type mdcKey struct{}
+
+// MDCPut returns a copy of ctx carrying the key/value pair in its mapped
+// diagnostic context (MDC). Printers got from the builtin Logger with the
+// returned context carry all MDC key/value pairs as fields.
+// If the key is already exist, the existing value will be override.
+// Note that MDC is context-scoped rather than goroutine-local: only logs
+// printed with the returned context (or its children) carry the pair.
+func MDCPut(ctx context.Context, key string, value interface{}) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	old := MDCFields(ctx)
+	fields := make([]Field, 0, len(old)+1)
+	for _, field := range old {
+		if field.Key == key {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	fields = append(fields, Field{key, value})
+	return context.WithValue(ctx, mdcKey{}, fields)
+}
+
+// MDCRemove returns a copy of ctx whose mapped diagnostic context does not
+// contains the key. The ctx itself is not modified.
+func MDCRemove(ctx context.Context, key string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	old := MDCFields(ctx)
+	fields := make([]Field, 0, len(old))
+	for _, field := range old {
+		if field.Key == key {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return context.WithValue(ctx, mdcKey{}, fields)
+}
+
+// MDCFields returns the key/value pairs in the mapped diagnostic context of
+// ctx, in the order they were put. The returned slice should not be modified.
+func MDCFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(mdcKey{}).([]Field)
+	return fields
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestMDCPut(t *testing.T) {
+	w := &bytes.Buffer{}
+	ctx := MDCPut(context.Background(), "request_id", "r1")
+	ctx = MDCPut(ctx, "user", "mike")
+	buildStdLogger("", w).AtLevel(ctx, InfoLevel).Print("abc")
+	expect := "level=INFO logger= request_id=r1 user=mike abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	ctx = MDCPut(ctx, "request_id", "r2")
+	buildStdLogger("", w).AtLevel(ctx, InfoLevel).Print("abc")
+	expect = "level=INFO logger= user=mike request_id=r2 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestMDCRemove(t *testing.T) {
+	ctx := MDCPut(context.Background(), "request_id", "r1")
+	ctx = MDCPut(ctx, "user", "mike")
+	removed := MDCRemove(ctx, "request_id")
+	if got := MDCFields(removed); len(got) != 1 || got[0].Key != "user" {
+		t.Errorf("expect only user left, got %v", got)
+	}
+	if got := MDCFields(ctx); len(got) != 2 {
+		t.Errorf("expect the original context not modified, got %v", got)
+	}
+	if got := MDCFields(nil); got != nil {
+		t.Errorf("expect nil, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryOutPutter is an OutPutter storing the records in memory, so that
+// tests can assert on the levels and fields of records directly rather than
+// matching text. It is safe for concurrent use.
+type MemoryOutPutter struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+var _ OutPutter = (*MemoryOutPutter)(nil)
+
+// NewMemoryOutPutter create a MemoryOutPutter storing no records.
+func NewMemoryOutPutter() *MemoryOutPutter {
+	return &MemoryOutPutter{}
+}
+
+// OutPut stores the record. The fields are copied with their Valuers
+// resolved, and fields with empty key are skipped.
+func (m *MemoryOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, _ int) {
+	rec := Record{Name: name, Level: level, Msg: msg, Fields: resolveFields(ctx, name, fields)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, rec)
+}
+
+// Records returns a copy of the stored records, in the order they were
+// output.
+func (m *MemoryOutPutter) Records() []Record {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	records := make([]Record, len(m.records))
+	copy(records, m.records)
+	return records
+}
+
+// Reset removes the stored records.
+func (m *MemoryOutPutter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = nil
+}
+
+func (m *MemoryOutPutter) Describe() string {
+	return "memory"
+}
+
+// UseMemoryProvider register a LoggerProvider whose Loggers output to a new
+// MemoryOutPutter, which is returned, with the options applied to every
+// Logger. The returned function restores the LoggerProvider registered
+// before. It is meant for tests:
+//
+//	out, restore := log.UseMemoryProvider()
+//	defer restore()
+func UseMemoryProvider(opts ...StdLoggerOption) (*MemoryOutPutter, func()) {
+	m := NewMemoryOutPutter()
+	old := _loggerProvider.Load()
+	UseProvider(NewStdLoggerProvider(m, opts...))
+	return m, func() {
+		_loggerProvider.Store(old)
+	}
+}
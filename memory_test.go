@@ -0,0 +1,52 @@
+package log
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMemoryOutPutter(t *testing.T) {
+	m := NewMemoryOutPutter()
+	logger := NewStdLogger("mem", m)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.AtLevel(context.Background(), InfoLevel).Print("abc")
+		}()
+	}
+	wg.Wait()
+	if got := len(m.Records()); got != 4 {
+		t.Errorf("expect 4 records, got %d", got)
+	}
+	m.Reset()
+	user := Valuer(func(ctx context.Context) interface{} { return "mike" })
+	logger.AtLevel(context.Background(), WarnLevel).With("user", user).Print("abc")
+	expect := []Record{{
+		Name:   "mem",
+		Level:  WarnLevel,
+		Msg:    "abc",
+		Fields: []Field{{LevelKey, WarnLevel}, {LoggerKey, "mem"}, {"user", "mike"}},
+	}}
+	if got := m.Records(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %+v, got %+v", expect, got)
+	}
+}
+
+func TestUseMemoryProvider(t *testing.T) {
+	op := getLoggerProvider()
+	defer UseProvider(op)
+	UseProvider(nopProvider)
+	m, restore := UseMemoryProvider()
+	Get("mem").AtLevel(context.Background(), InfoLevel).Print("abc")
+	if records := m.Records(); len(records) != 1 || records[0].Msg != "abc" {
+		t.Errorf("expect the record stored, got %+v", records)
+	}
+	restore()
+	if _, ok := Get("mem").(*nopLogger); !ok {
+		t.Errorf("expect the provider restored, got %T", Get("mem"))
+	}
+}
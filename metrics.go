@@ -0,0 +1,29 @@
+package log
+
+import (
+	"unsafe"
+
+	ua "go.uber.org/atomic"
+)
+
+// MetricsHook is a function that increments the metric by delta.
+type MetricsHook func(metric string, delta int64)
+
+var _metricsHook = ua.NewUnsafePointer(unsafe.Pointer((*MetricsHook)(nil)))
+
+// SetMetricsHook register a MetricsHook which is invoked for every metric
+// attached via Printer.Count when the line is emitted. By default, or if nil
+// is passed, counting is a no-op.
+// If this function is called more than once, the last call wins.
+func SetMetricsHook(hook MetricsHook) {
+	_metricsHook.Store(unsafe.Pointer(&hook))
+}
+
+// countMetric invokes the registered MetricsHook.
+func countMetric(metric string, delta int64) {
+	hook := (*MetricsHook)(_metricsHook.Load())
+	if hook == nil || *hook == nil {
+		return
+	}
+	(*hook)(metric, delta)
+}
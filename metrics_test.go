@@ -0,0 +1,56 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestSetMetricsHook(t *testing.T) {
+	defer SetMetricsHook(nil)
+	var metrics []string
+	var deltas []int64
+	SetMetricsHook(func(metric string, delta int64) {
+		metrics = append(metrics, metric)
+		deltas = append(deltas, delta)
+	})
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w).Count("login", 2)
+	if len(metrics) != 0 {
+		t.Errorf("expect hook not fired before the line is emitted, got %v", metrics)
+	}
+	printer.Print("logged in")
+	if len(metrics) != 1 || metrics[0] != "login" || deltas[0] != 2 {
+		t.Errorf("expect hook fired with login/2, got %v/%v", metrics, deltas)
+	}
+	expect := "level=INFO logger= login=2 logged in\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	NewNopPrinter().Count("login", 1).Print("nothing")
+	if len(metrics) != 1 {
+		t.Errorf("expect hook not fired by nop printer, got %v", metrics)
+	}
+	SetMetricsHook(nil)
+	buildStdPrinter(context.Background(), w).Count("login", 1).Print("logged in")
+}
+
+func TestSetMetricsHook_AtLevels(t *testing.T) {
+	defer SetMetricsHook(nil)
+	var deltas []int64
+	SetMetricsHook(func(metric string, delta int64) {
+		deltas = append(deltas, delta)
+	})
+	w := &bytes.Buffer{}
+	logger := buildStdLogger("", w)
+	logger.AtLevels(context.Background(), InfoLevel, WarnLevel, ErrorLevel).Count("login", 2).Print("logged in")
+	if len(deltas) != 1 || deltas[0] != 2 {
+		t.Errorf("expect hook fired once with 2, got %v", deltas)
+	}
+	expect := "level=INFO logger= login=2 logged in\n" +
+		"level=WARN logger= login=2 logged in\n" +
+		"level=ERROR logger= login=2 logged in\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"sort"
+	"sync"
+
+	ua "go.uber.org/atomic"
+)
+
+// maxTrackedLoggerNames is the limit of logger names can be tracked.
+const maxTrackedLoggerNames = 1024
+
+var (
+	_trackLoggerNames = ua.NewBool(false)
+	_loggerNames      = &sync.Map{}
+	_loggerNamesCount = ua.NewInt32(0)
+)
+
+// TrackLoggerNames enable or disable tracking names of the builtin Loggers
+// in use. Once enabled, every distinct name passed to AtLevel is recorded,
+// and can be listed via KnownLoggerNames. At most 1024 names are tracked.
+// It is disabled by default to avoid overhead.
+func TrackLoggerNames(enable bool) {
+	_trackLoggerNames.Store(enable)
+}
+
+// KnownLoggerNames returns the tracked logger names in sorted order.
+func KnownLoggerNames() []string {
+	var names []string
+	_loggerNames.Range(func(key, _ interface{}) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+func trackLoggerName(name string) {
+	if !_trackLoggerNames.Load() {
+		return
+	}
+	if _, ok := _loggerNames.Load(name); ok {
+		return
+	}
+	if _loggerNamesCount.Inc() > maxTrackedLoggerNames {
+		_loggerNamesCount.Dec()
+		return
+	}
+	if _, loaded := _loggerNames.LoadOrStore(name, struct{}{}); loaded {
+		_loggerNamesCount.Dec()
+	}
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func resetLoggerNames() {
+	TrackLoggerNames(false)
+	_loggerNames = &sync.Map{}
+	_loggerNamesCount.Store(0)
+}
+
+func TestKnownLoggerNames(t *testing.T) {
+	defer resetLoggerNames()
+	resetLoggerNames()
+	buildStdLogger("untracked", ioutil.Discard).AtLevel(context.Background(), InfoLevel)
+	if got := KnownLoggerNames(); len(got) != 0 {
+		t.Errorf("expect nothing tracked, got %v", got)
+	}
+	TrackLoggerNames(true)
+	for _, name := range []string{"c", "a", "b", "a"} {
+		buildStdLogger(name, ioutil.Discard).AtLevel(context.Background(), DebugLevel).Print("abc")
+	}
+	expect := []string{"a", "b", "c"}
+	if got := KnownLoggerNames(); !reflect.DeepEqual(got, expect) {
+		t.Errorf("expect %v, got %v", expect, got)
+	}
+}
+
+func TestKnownLoggerNames_Limit(t *testing.T) {
+	defer resetLoggerNames()
+	resetLoggerNames()
+	TrackLoggerNames(true)
+	for i := 0; i < maxTrackedLoggerNames+10; i++ {
+		trackLoggerName(fmt.Sprintf("logger%d", i))
+	}
+	if got := len(KnownLoggerNames()); got != maxTrackedLoggerNames {
+		t.Errorf("expect %d, got %d", maxTrackedLoggerNames, got)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/go-kita/log/otel
+
+go 1.18
+
+require (
+	github.com/go-kita/log v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/sdk v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+)
+
+require (
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.uber.org/atomic v1.8.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+)
+
+replace github.com/go-kita/log => ../
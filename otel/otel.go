@@ -0,0 +1,150 @@
+// Package otel provides OpenTelemetry integrations for OutPutters.
+// It is a separated module so that the OpenTelemetry dependency is kept out
+// of the core logging module.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-kita/log"
+)
+
+// Define the span event name and attribute keys used to annotate spans.
+const (
+	// EventName is the name of span events added for log records.
+	EventName = "log"
+	// MessageKey is the attribute key for the message.
+	MessageKey = "log.message"
+	// LevelKey is the attribute key for the level.
+	LevelKey = "log.level"
+	// LoggerKey is the attribute key for the logger name.
+	LoggerKey = "log.logger"
+)
+
+// annotateOutPutter annotates spans with records before delegating.
+type annotateOutPutter struct {
+	underlying log.OutPutter
+	minLevel   log.Level
+}
+
+// FilterAnnotateSpan build a OutPutter wrapping the provided OutPutter. For
+// records at or above minLevel, an event named EventName is added to the
+// recording span of the context of the record, with the message, level,
+// logger name and fields as attributes, before the record is output by o.
+// It ties high-severity logs into distributed traces.
+func FilterAnnotateSpan(o log.OutPutter, minLevel log.Level) log.OutPutter {
+	if o == nil {
+		return o
+	}
+	return &annotateOutPutter{
+		underlying: o,
+		minLevel:   minLevel,
+	}
+}
+
+func (a *annotateOutPutter) OutPut(
+	ctx context.Context, name string, level log.Level, msg string, fields []log.Field, callDepth int) {
+	if level >= a.minLevel && ctx != nil {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			attrs := make([]attribute.KeyValue, 0, len(fields)+3)
+			attrs = append(attrs,
+				attribute.String(MessageKey, msg),
+				attribute.String(LevelKey, level.String()),
+				attribute.String(LoggerKey, name),
+			)
+			for _, field := range fields {
+				if len(field.Key) == 0 || field.Key == log.LevelKey || field.Key == log.LoggerKey {
+					continue
+				}
+				attrs = append(attrs, attributeOf(field.Key, log.NamedValue(ctx, name, field.Value)))
+			}
+			span.AddEvent(EventName, trace.WithAttributes(attrs...))
+		}
+	}
+	a.underlying.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (a *annotateOutPutter) Describe() string {
+	return fmt.Sprintf("annotate_span(%s)", a.minLevel)
+}
+
+// Unwrap returns the wrapped OutPutter.
+func (a *annotateOutPutter) Unwrap() log.OutPutter {
+	return a.underlying
+}
+
+// Define the field keys of the IDs of the span context.
+const (
+	// TraceIDKey is the field key for the trace ID.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the field key for the span ID.
+	SpanIDKey = "span_id"
+)
+
+// traceFieldsOutPutter adds the span context IDs to records before
+// delegating.
+type traceFieldsOutPutter struct {
+	underlying log.OutPutter
+}
+
+// FilterTraceFields build a OutPutter wrapping the provided OutPutter. The
+// trace ID and span ID of the span context carried by the context of a record
+// (see trace.SpanContextFromContext) are appended to the fields as TraceIDKey
+// and SpanIDKey, in hex. If the context carries no valid span context, the
+// fields are omitted rather than output empty.
+// It is a filter rather than Valuers registered by RegisterContextField,
+// since a Valuer can't omit its field.
+func FilterTraceFields(o log.OutPutter) log.OutPutter {
+	if o == nil {
+		return o
+	}
+	return &traceFieldsOutPutter{underlying: o}
+}
+
+func (f *traceFieldsOutPutter) OutPut(
+	ctx context.Context, name string, level log.Level, msg string, fields []log.Field, callDepth int) {
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			merged := make([]log.Field, 0, len(fields)+2)
+			merged = append(merged, fields...)
+			fields = append(merged,
+				log.Field{Key: TraceIDKey, Value: sc.TraceID().String()},
+				log.Field{Key: SpanIDKey, Value: sc.SpanID().String()},
+			)
+		}
+	}
+	f.underlying.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (f *traceFieldsOutPutter) Describe() string {
+	return "trace_fields"
+}
+
+// Unwrap returns the wrapped OutPutter.
+func (f *traceFieldsOutPutter) Unwrap() log.OutPutter {
+	return f.underlying
+}
+
+// attributeOf converts a resolved field value to an attribute.
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,91 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	stdlog "log"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/go-kita/log"
+)
+
+func TestFilterAnnotateSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	w := &bytes.Buffer{}
+	o := FilterAnnotateSpan(log.NewStdOutPutter(stdlog.New(w, "", 0)), log.ErrorLevel)
+	fields := []log.Field{
+		{Key: log.LevelKey, Value: log.WarnLevel},
+		{Key: log.LoggerKey, Value: "db"},
+		{Key: "retries", Value: 3},
+	}
+	o.OutPut(ctx, "db", log.WarnLevel, "slow query", fields, 0)
+	fields[0].Value = log.ErrorLevel
+	o.OutPut(ctx, "db", log.ErrorLevel, "connect failed", fields, 0)
+	span.End()
+	if expect := "level=WARN logger=db retries=3 slow query\nlevel=ERROR logger=db retries=3 connect failed\n"; w.String() != expect {
+		t.Errorf("expect %q, got %q", expect, w.String())
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expect 1 span, got %d", len(spans))
+	}
+	events := spans[0].Events()
+	if len(events) != 1 {
+		t.Fatalf("expect 1 event, got %d", len(events))
+	}
+	if events[0].Name != EventName {
+		t.Errorf("expect event %q, got %q", EventName, events[0].Name)
+	}
+	expect := []attribute.KeyValue{
+		attribute.String(MessageKey, "connect failed"),
+		attribute.String(LevelKey, "ERROR"),
+		attribute.String(LoggerKey, "db"),
+		attribute.Int("retries", 3),
+	}
+	if got := events[0].Attributes; len(got) != len(expect) {
+		t.Fatalf("expect %v, got %v", expect, got)
+	}
+	for i, attr := range events[0].Attributes {
+		if attr != expect[i] {
+			t.Errorf("expect %v, got %v", expect[i], attr)
+		}
+	}
+}
+
+func TestFilterAnnotateSpan_NoSpan(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := FilterAnnotateSpan(log.NewStdOutPutter(stdlog.New(w, "", 0)), log.ErrorLevel)
+	o.OutPut(context.Background(), "db", log.ErrorLevel, "abc", nil, 0)
+	if expect := "abc\n"; w.String() != expect {
+		t.Errorf("expect %q, got %q", expect, w.String())
+	}
+	if FilterAnnotateSpan(nil, log.ErrorLevel) != nil {
+		t.Errorf("expect nil for nil OutPutter")
+	}
+}
+
+func TestFilterTraceFields(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	w := &bytes.Buffer{}
+	o := FilterTraceFields(log.NewStdOutPutter(stdlog.New(w, "", 0)))
+	fields := []log.Field{{Key: "retries", Value: 3}}
+	o.OutPut(context.Background(), "db", log.InfoLevel, "no span", fields, 0)
+	o.OutPut(ctx, "db", log.InfoLevel, "in span", fields, 0)
+	sc := span.SpanContext()
+	expect := "retries=3 no span\n" +
+		"retries=3 trace_id=" + sc.TraceID().String() + " span_id=" + sc.SpanID().String() + " in span\n"
+	if w.String() != expect {
+		t.Errorf("expect %q, got %q", expect, w.String())
+	}
+	if len(fields) != 1 {
+		t.Errorf("expect fields not modified, got %v", fields)
+	}
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"fmt"
+
+	ua "go.uber.org/atomic"
+)
+
+// ReservedKeyPolicy decides what happens when a reserved key, which is set
+// by the builtin Logger itself, is used as the key of Printer.With.
+type ReservedKeyPolicy int32
+
+// Define ReservedKeyPolicies.
+const (
+	// ReservedKeyAllow allows reserved keys silently. It is the default.
+	ReservedKeyAllow ReservedKeyPolicy = iota
+	// ReservedKeyWarn allows reserved keys but writes a diagnostic to the
+	// standard error.
+	ReservedKeyWarn
+	// ReservedKeyPanic panics on reserved keys. It is useful to catch misuse
+	// in tests.
+	ReservedKeyPanic
+)
+
+// _reservedKeys are the field keys set by the builtin Logger itself.
+var _reservedKeys = map[string]bool{
+	LevelKey:          true,
+	LoggerKey:         true,
+	RunIDKey:          true,
+	LevelThresholdKey: true,
+	TimestampKey:      true,
+	CallerKey:         true,
+}
+
+var _reservedKeyPolicy = ua.NewInt32(int32(ReservedKeyAllow))
+
+// SetReservedKeyPolicy set the ReservedKeyPolicy for the builtin Printer.
+func SetReservedKeyPolicy(policy ReservedKeyPolicy) {
+	_reservedKeyPolicy.Store(int32(policy))
+}
+
+// checkReservedKey applies the ReservedKeyPolicy to the key.
+func checkReservedKey(key string) {
+	policy := ReservedKeyPolicy(_reservedKeyPolicy.Load())
+	if policy == ReservedKeyAllow || !_reservedKeys[key] {
+		return
+	}
+	msg := fmt.Sprintf("log: reserved key %q used as field key", key)
+	if policy == ReservedKeyPanic {
+		panic(msg)
+	}
+	_, _ = fmt.Fprintln(_diagnosticOutput, msg)
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSetReservedKeyPolicy(t *testing.T) {
+	w := &bytes.Buffer{}
+	_diagnosticOutput = w
+	defer func() {
+		_diagnosticOutput = os.Stderr
+		SetReservedKeyPolicy(ReservedKeyAllow)
+	}()
+	withKey := func(key string) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		buildStdPrinter(context.Background(), ioutil.Discard).With(key, "value")
+		return false
+	}
+
+	SetReservedKeyPolicy(ReservedKeyAllow)
+	if withKey(LevelKey) || w.Len() != 0 {
+		t.Errorf("expect allowed silently, got %q", w.String())
+	}
+
+	SetReservedKeyPolicy(ReservedKeyWarn)
+	if withKey(LoggerKey) {
+		t.Errorf("expect no panic in warn mode")
+	}
+	expect := "log: reserved key \"logger\" used as field key\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+
+	w.Reset()
+	SetReservedKeyPolicy(ReservedKeyPanic)
+	if !withKey(LevelKey) {
+		t.Errorf("expect panic in panic mode")
+	}
+	if withKey("module") {
+		t.Errorf("expect no panic for a non-reserved key")
+	}
+	if w.Len() != 0 {
+		t.Errorf("expect no diagnostic, got %q", w.String())
+	}
+}
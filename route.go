@@ -0,0 +1,39 @@
+package log
+
+import "context"
+
+// routingOutPutter selects the OutPutter per record by the context.
+type routingOutPutter struct {
+	pick func(ctx context.Context) OutPutter
+	def  OutPutter
+}
+
+// NewContextRoutingOutPutter create a OutPutter which selects the OutPutter
+// to output each record by calling pick with the context of the record, i.e.
+// to route the logs of a tenant to its own sink. If pick returns nil, the
+// record is output to def. If def is nil too, the record is discarded.
+func NewContextRoutingOutPutter(pick func(ctx context.Context) OutPutter, def OutPutter) OutPutter {
+	return &routingOutPutter{
+		pick: pick,
+		def:  def,
+	}
+}
+
+func (r *routingOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	var o OutPutter
+	if r.pick != nil {
+		o = r.pick(ctx)
+	}
+	if o == nil {
+		o = r.def
+	}
+	if o == nil {
+		return
+	}
+	o.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (r *routingOutPutter) Describe() string {
+	return "route"
+}
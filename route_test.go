@@ -0,0 +1,39 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestNewContextRoutingOutPutter(t *testing.T) {
+	wa, wb, wd := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	sinks := map[string]OutPutter{
+		"a": NewStdOutPutter(log.New(wa, "", 0)),
+		"b": NewStdOutPutter(log.New(wb, "", 0)),
+	}
+	o := NewContextRoutingOutPutter(func(ctx context.Context) OutPutter {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return sinks[tenant]
+	}, NewStdOutPutter(log.New(wd, "", 0)))
+	o.OutPut(context.WithValue(context.Background(), tenantKey{}, "a"), "", InfoLevel, "to a", nil, 0)
+	o.OutPut(context.WithValue(context.Background(), tenantKey{}, "b"), "", InfoLevel, "to b", nil, 0)
+	o.OutPut(context.Background(), "", InfoLevel, "to default", nil, 0)
+	for _, test := range []struct {
+		w      *bytes.Buffer
+		expect string
+	}{
+		{wa, "to a\n"},
+		{wb, "to b\n"},
+		{wd, "to default\n"},
+	} {
+		if got := test.w.String(); got != test.expect {
+			t.Errorf("expect %q, got %q", test.expect, got)
+		}
+	}
+	o = NewContextRoutingOutPutter(nil, nil)
+	o.OutPut(context.Background(), "", InfoLevel, "discarded", nil, 0)
+}
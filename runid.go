@@ -0,0 +1,25 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// _runID identifies the current process run.
+var _runID = newRunID()
+
+func newRunID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// RunID returns the ID of the current process run. It is generated once at
+// init, so it is stable for the process lifetime and unique per start.
+func RunID() string {
+	return _runID
+}
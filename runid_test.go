@@ -0,0 +1,37 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestRunID(t *testing.T) {
+	if RunID() == "" {
+		t.Errorf("expect not empty run ID")
+	}
+	if RunID() != RunID() {
+		t.Errorf("expect run ID stable")
+	}
+	if newRunID() == RunID() {
+		t.Errorf("expect run IDs unique")
+	}
+}
+
+func TestWithRunID(t *testing.T) {
+	w := &bytes.Buffer{}
+	provider := NewStdLoggerProvider(NewStdOutPutter(log.New(w, "", 0)), WithRunID(true))
+	provider("a").AtLevel(context.Background(), InfoLevel).Print("abc")
+	provider("b").AtLevel(context.Background(), InfoLevel).Print("abc")
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 lines, got %q", w.String())
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, " "+RunIDKey+"="+RunID()+" ") {
+			t.Errorf("expect run ID %q in line, got %q", RunID(), line)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package log
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	ua "go.uber.org/atomic"
+)
+
+// Sampler decides whether a record should be output.
+type Sampler interface {
+	// Sample reports whether the record should be output.
+	Sample(ctx context.Context, name string, level Level, msg string) bool
+}
+
+// SamplerFunc is an adapter to allow the use of ordinary functions as
+// Sampler.
+type SamplerFunc func(ctx context.Context, name string, level Level, msg string) bool
+
+// Sample calls f(ctx, name, level, msg).
+func (f SamplerFunc) Sample(ctx context.Context, name string, level Level, msg string) bool {
+	return f(ctx, name, level, msg)
+}
+
+// samplingOutPutter is an OutPutter consults a Sampler per record.
+type samplingOutPutter struct {
+	underlying OutPutter
+	sampler    Sampler
+}
+
+// FilterWithSampler build a OutPutter wrapping the provided OutPutter. Each
+// record will be skipped if the Sampler decides not to sample it.
+func FilterWithSampler(o OutPutter, s Sampler) OutPutter {
+	if o == nil || s == nil {
+		return o
+	}
+	return &samplingOutPutter{
+		underlying: o,
+		sampler:    s,
+	}
+}
+
+func (s *samplingOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	if !s.sampler.Sample(ctx, name, level, msg) {
+		return
+	}
+	s.underlying.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (s *samplingOutPutter) Describe() string {
+	return "sample"
+}
+
+func (s *samplingOutPutter) Unwrap() OutPutter {
+	return s.underlying
+}
+
+// ======== tick based =========
+
+// samplingKey identifies records counted together by the tick Sampler.
+type samplingKey struct {
+	level Level
+	msg   string
+}
+
+// tickSampler is a Sampler samples the first N records with the same level
+// and message per tick, then every Mth record afterwards.
+type tickSampler struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	windowEnd time.Time
+	counts    map[samplingKey]int
+}
+
+// NewTickSampler create a Sampler which samples the first `first` records
+// with the same level and message in each tick, then one in `thereafter`
+// records afterwards. If thereafter is zero or negative, all records after
+// the first ones in the tick are dropped.
+func NewTickSampler(tick time.Duration, first, thereafter int) Sampler {
+	return &tickSampler{
+		tick:       tick,
+		first:      first,
+		thereafter: thereafter,
+		now:        clockNow,
+		counts:     map[samplingKey]int{},
+	}
+}
+
+func (t *tickSampler) Sample(_ context.Context, _ string, level Level, msg string) bool {
+	now := t.now()
+	key := samplingKey{level, msg}
+	t.mu.Lock()
+	if !now.Before(t.windowEnd) {
+		t.windowEnd = now.Add(t.tick)
+		t.counts = map[samplingKey]int{}
+	}
+	t.counts[key]++
+	n := t.counts[key]
+	t.mu.Unlock()
+	if n <= t.first {
+		return true
+	}
+	if t.thereafter <= 0 {
+		return false
+	}
+	return (n-t.first)%t.thereafter == 0
+}
+
+// FilterSample build a OutPutter wrapping the provided OutPutter, which
+// outputs the first `first` records with the same level and message in each
+// tick, then one in `thereafter` records afterwards, like the sampler of zap.
+// It is a shortcut of FilterWithSampler with NewTickSampler.
+func FilterSample(o OutPutter, tick time.Duration, first, thereafter int) OutPutter {
+	return FilterWithSampler(o, NewTickSampler(tick, first, thereafter))
+}
+
+// ======== probabilistic =========
+
+// NewRandomSampler create a Sampler which samples records with the provided
+// probability in the range [0, 1].
+func NewRandomSampler(rate float64) Sampler {
+	return SamplerFunc(func(_ context.Context, _ string, _ Level, _ string) bool {
+		return rand.Float64() < rate
+	})
+}
+
+// ======== rate limited =========
+
+// tokenBucket is a token bucket rate limiter.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSecond, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   float64(perSecond),
+		burst:  float64(burst),
+		now:    clockNow,
+		tokens: float64(burst),
+	}
+}
+
+// take reports whether a token is available and takes it.
+func (b *tokenBucket) take() bool {
+	now := b.now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// NewRateSampler create a Sampler which samples at most perSecond records per
+// second, allowing bursts of up to burst records. Time is measured by the
+// registered Clock (see UseClock).
+func NewRateSampler(perSecond, burst int) Sampler {
+	bucket := newTokenBucket(perSecond, burst)
+	return SamplerFunc(func(_ context.Context, _ string, _ Level, _ string) bool {
+		return bucket.take()
+	})
+}
+
+// DropCounter is implemented by OutPutters dropping records, i.e. the one
+// created by FilterGlobalRateLimit.
+type DropCounter interface {
+	// Dropped returns the number of records dropped.
+	Dropped() int64
+}
+
+// rateLimitOutPutter is an OutPutter drops records exceeding a rate.
+type rateLimitOutPutter struct {
+	underlying OutPutter
+	bucket     *tokenBucket
+	dropped    ua.Int64
+}
+
+// FilterGlobalRateLimit build a OutPutter wrapping the provided OutPutter,
+// which outputs at most perSecond records per second, allowing bursts of up to
+// burst records, whatever Logger they are from, i.e. to cap the cost of a paid
+// sink. Records exceeding the rate are dropped, and counted: the returned
+// OutPutter implements DropCounter. Time is measured by the registered Clock
+// (see UseClock).
+func FilterGlobalRateLimit(o OutPutter, perSecond, burst int) OutPutter {
+	if o == nil {
+		return o
+	}
+	return &rateLimitOutPutter{
+		underlying: o,
+		bucket:     newTokenBucket(perSecond, burst),
+	}
+}
+
+func (r *rateLimitOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	if !r.bucket.take() {
+		r.dropped.Inc()
+		return
+	}
+	r.underlying.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (r *rateLimitOutPutter) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+func (r *rateLimitOutPutter) Describe() string {
+	return "rate_limit"
+}
+
+func (r *rateLimitOutPutter) Unwrap() OutPutter {
+	return r.underlying
+}
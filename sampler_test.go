@@ -0,0 +1,171 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFilterWithSampler(t *testing.T) {
+	w := &bytes.Buffer{}
+	n := 0
+	o := FilterWithSampler(NewStdOutPutter(log.New(w, "", 0)),
+		SamplerFunc(func(_ context.Context, _ string, _ Level, _ string) bool {
+			n++
+			return n%2 == 0
+		}))
+	for i := 0; i < 4; i++ {
+		o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+	}
+	expect := "abc\nabc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if o = FilterWithSampler(nil, NewRandomSampler(1)); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
+func TestNewTickSampler(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewTickSampler(time.Second, 2, 3)
+	s.(*tickSampler).now = func() time.Time { return now }
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, s.Sample(context.Background(), "", InfoLevel, "abc"))
+	}
+	expect := []bool{true, true, false, false, true, false, false, true}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("expect %v, got %v", expect, got)
+			break
+		}
+	}
+	if !s.Sample(context.Background(), "", WarnLevel, "abc") {
+		t.Errorf("expect a different level counted separately")
+	}
+	now = now.Add(time.Second)
+	if !s.Sample(context.Background(), "", InfoLevel, "abc") {
+		t.Errorf("expect counters reset in a new tick")
+	}
+	s = NewTickSampler(time.Second, 1, 0)
+	s.(*tickSampler).now = func() time.Time { return now }
+	if !s.Sample(context.Background(), "", InfoLevel, "abc") || s.Sample(context.Background(), "", InfoLevel, "abc") {
+		t.Errorf("expect only the first sampled")
+	}
+}
+
+func TestFilterSample(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	out := &bytes.Buffer{}
+	o := FilterSample(NewStdOutPutter(log.New(out, "", 0)), time.Second, 2, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 8; j++ {
+				o.OutPut(context.Background(), "", ErrorLevel, "storm", nil, 0)
+			}
+		}()
+	}
+	wg.Wait()
+	// 2 first, then 1 in 3 of the other 30
+	if got := strings.Count(out.String(), "storm\n"); got != 12 {
+		t.Errorf("expect 12 records, got %d", got)
+	}
+	out.Reset()
+	clock.now = clock.now.Add(time.Second)
+	o.OutPut(context.Background(), "", ErrorLevel, "storm", nil, 0)
+	o.OutPut(context.Background(), "", InfoLevel, "storm", nil, 0)
+	if expect := "storm\nstorm\n"; out.String() != expect {
+		t.Errorf("expect %q, got %q", expect, out.String())
+	}
+}
+
+func TestNewRandomSampler(t *testing.T) {
+	always, never := NewRandomSampler(1), NewRandomSampler(0)
+	for i := 0; i < 100; i++ {
+		if !always.Sample(context.Background(), "", InfoLevel, "abc") {
+			t.Fatalf("expect always sampled")
+		}
+		if never.Sample(context.Background(), "", InfoLevel, "abc") {
+			t.Fatalf("expect never sampled")
+		}
+	}
+}
+
+func TestNewRateSampler(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	s := NewRateSampler(2, 3)
+	count := func(n int) int {
+		c := 0
+		for i := 0; i < n; i++ {
+			if s.Sample(context.Background(), "", InfoLevel, "abc") {
+				c++
+			}
+		}
+		return c
+	}
+	if c := count(10); c != 3 {
+		t.Errorf("expect burst of 3, got %d", c)
+	}
+	clock.now = clock.now.Add(time.Second)
+	if c := count(10); c != 2 {
+		t.Errorf("expect 2 per second, got %d", c)
+	}
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	if c := count(10); c != 1 {
+		t.Errorf("expect 1 per half second, got %d", c)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if c := count(10); c != 3 {
+		t.Errorf("expect refill capped at burst of 3, got %d", c)
+	}
+	s = NewRateSampler(1, 0)
+	if c := count(10); c != 1 {
+		t.Errorf("expect burst less than 1 taken as 1, got %d", c)
+	}
+}
+
+func TestFilterGlobalRateLimit(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	out := &countOutPutter{}
+	o := FilterGlobalRateLimit(out, 10, 10)
+	flood := func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				for j := 0; j < 25; j++ {
+					o.OutPut(context.Background(), name, ErrorLevel, "storm", nil, 0)
+				}
+			}(fmt.Sprintf("logger%d", i))
+		}
+		wg.Wait()
+	}
+	flood()
+	if out.n != 10 || o.(DropCounter).Dropped() != 90 {
+		t.Errorf("expect 10 emitted and 90 dropped, got %d and %d", out.n, o.(DropCounter).Dropped())
+	}
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	flood()
+	if out.n != 15 || o.(DropCounter).Dropped() != 185 {
+		t.Errorf("expect 15 emitted and 185 dropped, got %d and %d", out.n, o.(DropCounter).Dropped())
+	}
+	if FilterGlobalRateLimit(nil, 1, 1) != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
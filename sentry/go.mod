@@ -0,0 +1,16 @@
+module github.com/go-kita/log/sentry
+
+go 1.18
+
+require (
+	github.com/getsentry/sentry-go v0.27.0
+	github.com/go-kita/log v0.0.0-00010101000000-000000000000
+)
+
+require (
+	go.uber.org/atomic v1.8.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
+	golang.org/x/text v0.8.0 // indirect
+)
+
+replace github.com/go-kita/log => ../
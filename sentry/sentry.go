@@ -0,0 +1,92 @@
+// Package sentry provides an OutPutter forwarding logs to Sentry.
+// It is a separated module so that the Sentry SDK dependency is kept out of
+// the core logging module.
+package sentry
+
+import (
+	"context"
+
+	sentrygo "github.com/getsentry/sentry-go"
+
+	"github.com/go-kita/log"
+)
+
+// LoggerTag is the Sentry tag key for the logger name.
+const LoggerTag = "logger"
+
+// outPutter is an OutPutter forwarding records to Sentry.
+type outPutter struct {
+	hub      *sentrygo.Hub
+	minLevel log.Level
+}
+
+// NewSentryOutPutter create a OutPutter forwarding records to the Sentry
+// project identified by dsn.
+// Records at or above minLevel are captured as Sentry events, with the
+// message, the level, the logger name as a tag and the fields as extra data.
+// Records below minLevel are buffered as breadcrumbs, which are sent along
+// with the following events.
+func NewSentryOutPutter(dsn string, minLevel log.Level) (log.OutPutter, error) {
+	return NewSentryOutPutterWithOptions(sentrygo.ClientOptions{Dsn: dsn}, minLevel)
+}
+
+// NewSentryOutPutterWithOptions is like NewSentryOutPutter but creates the
+// Sentry client with the provided options.
+func NewSentryOutPutterWithOptions(options sentrygo.ClientOptions, minLevel log.Level) (log.OutPutter, error) {
+	client, err := sentrygo.NewClient(options)
+	if err != nil {
+		return nil, err
+	}
+	return &outPutter{
+		hub:      sentrygo.NewHub(client, sentrygo.NewScope()),
+		minLevel: minLevel,
+	}, nil
+}
+
+func (o *outPutter) OutPut(
+	ctx context.Context, name string, level log.Level, msg string, fields []log.Field, _ int) {
+	data := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if len(field.Key) == 0 {
+			continue
+		}
+		data[field.Key] = log.NamedValue(ctx, name, field.Value)
+	}
+	if level < o.minLevel {
+		o.hub.AddBreadcrumb(&sentrygo.Breadcrumb{
+			Type:     "default",
+			Category: name,
+			Message:  msg,
+			Data:     data,
+			Level:    sentryLevel(level),
+		}, nil)
+		return
+	}
+	event := sentrygo.NewEvent()
+	event.Message = msg
+	event.Level = sentryLevel(level)
+	event.Logger = name
+	event.Tags[LoggerTag] = name
+	event.Extra = data
+	o.hub.CaptureEvent(event)
+}
+
+func (o *outPutter) Describe() string {
+	return "sentry"
+}
+
+// sentryLevel maps Level to sentry Level.
+func sentryLevel(level log.Level) sentrygo.Level {
+	switch {
+	case level <= log.DebugLevel:
+		return sentrygo.LevelDebug
+	case level == log.InfoLevel:
+		return sentrygo.LevelInfo
+	case level == log.WarnLevel:
+		return sentrygo.LevelWarning
+	case level == log.ErrorLevel:
+		return sentrygo.LevelError
+	default:
+		return sentrygo.LevelFatal
+	}
+}
@@ -0,0 +1,89 @@
+package sentry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sentrygo "github.com/getsentry/sentry-go"
+
+	"github.com/go-kita/log"
+)
+
+// fakeTransport records the events sent.
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentrygo.Event
+}
+
+func (t *fakeTransport) Configure(_ sentrygo.ClientOptions) {
+}
+
+func (t *fakeTransport) SendEvent(event *sentrygo.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *fakeTransport) Flush(_ time.Duration) bool {
+	return true
+}
+
+func TestNewSentryOutPutter(t *testing.T) {
+	transport := &fakeTransport{}
+	o, err := NewSentryOutPutterWithOptions(sentrygo.ClientOptions{
+		Dsn:       "https://public@sentry.example.com/1",
+		Transport: transport,
+	}, log.ErrorLevel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	o.OutPut(ctx, "db", log.InfoLevel, "connecting", []log.Field{{Key: "host", Value: "db1"}}, 0)
+	if len(transport.events) != 0 {
+		t.Fatalf("expect no event for info record, got %d", len(transport.events))
+	}
+	o.OutPut(ctx, "db", log.ErrorLevel, "connect failed", []log.Field{
+		{Key: log.LevelKey, Value: log.ErrorLevel},
+		{Key: "host", Value: log.Valuer(func(ctx context.Context) interface{} { return "db1" })},
+	}, 0)
+	if len(transport.events) != 1 {
+		t.Fatalf("expect 1 event, got %d", len(transport.events))
+	}
+	event := transport.events[0]
+	if event.Message != "connect failed" || event.Level != sentrygo.LevelError {
+		t.Errorf("unexpected event message/level: %q/%q", event.Message, event.Level)
+	}
+	if event.Tags[LoggerTag] != "db" {
+		t.Errorf("expect logger tag db, got %q", event.Tags[LoggerTag])
+	}
+	if event.Extra["host"] != "db1" {
+		t.Errorf("expect resolved extra host=db1, got %v", event.Extra["host"])
+	}
+	if len(event.Breadcrumbs) != 1 || event.Breadcrumbs[0].Message != "connecting" {
+		t.Errorf("expect the info record as breadcrumb, got %v", event.Breadcrumbs)
+	}
+}
+
+func TestNewSentryOutPutter_InvalidDSN(t *testing.T) {
+	if _, err := NewSentryOutPutter("not a dsn", log.ErrorLevel); err == nil {
+		t.Errorf("expect error for invalid dsn")
+	}
+}
+
+func TestSentryLevel(t *testing.T) {
+	tests := map[log.Level]sentrygo.Level{
+		log.TraceLevel: sentrygo.LevelDebug,
+		log.DebugLevel: sentrygo.LevelDebug,
+		log.InfoLevel:  sentrygo.LevelInfo,
+		log.WarnLevel:  sentrygo.LevelWarning,
+		log.ErrorLevel: sentrygo.LevelError,
+		log.Level(3):   sentrygo.LevelFatal,
+	}
+	for level, expect := range tests {
+		if got := sentryLevel(level); got != expect {
+			t.Errorf("%v: expect %q, got %q", level, expect, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	_shutdownMu  sync.Mutex
+	_shutdownFns []*func() error
+)
+
+// RegisterShutdown register a function which flushes or closes a component,
+// i.e. a buffering OutPutter, to be called by Shutdown. The buffering
+// OutPutters of this package register themselves on construction, and
+// unregister themselves when closed.
+// The returned function unregisters fn, so a component closed before Shutdown
+// is neither called nor retained. It is safe to call more than once.
+func RegisterShutdown(fn func() error) (unregister func()) {
+	if fn == nil {
+		return func() {}
+	}
+	entry := &fn
+	_shutdownMu.Lock()
+	defer _shutdownMu.Unlock()
+	_shutdownFns = append(_shutdownFns, entry)
+	return func() {
+		_shutdownMu.Lock()
+		defer _shutdownMu.Unlock()
+		for i, e := range _shutdownFns {
+			if e == entry {
+				_shutdownFns = append(_shutdownFns[:i:i], _shutdownFns[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Shutdown calls the registered shutdown functions in the reverse order of
+// registration, and unregisters them. Errors of the functions are aggregated.
+// If ctx is done before all functions return, Shutdown returns the error of
+// ctx without waiting for the rest, which keep running in the background.
+// It is usually deferred in main:
+//
+//	defer log.Shutdown(ctx)
+func Shutdown(ctx context.Context) error {
+	_shutdownMu.Lock()
+	fns := _shutdownFns
+	_shutdownFns = nil
+	_shutdownMu.Unlock()
+	done := make(chan error, 1)
+	go func() {
+		var errs errorList
+		for i := len(fns) - 1; i >= 0; i-- {
+			if err := (*fns[i])(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		done <- errs.err()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
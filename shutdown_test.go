@@ -0,0 +1,79 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	var calls []string
+	RegisterShutdown(func() error {
+		calls = append(calls, "first")
+		return nil
+	})
+	RegisterShutdown(nil)
+	RegisterShutdown(func() error {
+		calls = append(calls, "second")
+		return errors.New("flush failed")
+	})
+	err := Shutdown(context.Background())
+	if err == nil || err.Error() != "flush failed" {
+		t.Errorf("expect flush failed error, got %v", err)
+	}
+	if expect := []string{"second", "first"}; !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expect %v, got %v", expect, calls)
+	}
+	calls = nil
+	if err = Shutdown(context.Background()); err != nil || len(calls) != 0 {
+		t.Errorf("expect nothing to run, got %v, %v", calls, err)
+	}
+}
+
+// shutdownFnsLen returns the number of registered shutdown functions.
+func shutdownFnsLen() int {
+	_shutdownMu.Lock()
+	defer _shutdownMu.Unlock()
+	return len(_shutdownFns)
+}
+
+func TestRegisterShutdown_Unregister(t *testing.T) {
+	var calls []string
+	RegisterShutdown(func() error {
+		calls = append(calls, "kept")
+		return nil
+	})
+	unregister := RegisterShutdown(func() error {
+		calls = append(calls, "unregistered")
+		return nil
+	})
+	unregister()
+	unregister()
+	RegisterShutdown(nil)()
+	if err := Shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if expect := []string{"kept"}; !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expect %v, got %v", expect, calls)
+	}
+}
+
+func TestShutdown_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	RegisterShutdown(func() error {
+		<-release
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expect %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expect Shutdown bounded by the deadline, took %v", elapsed)
+	}
+}
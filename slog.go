@@ -0,0 +1,151 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// slogHandler is a slog.Handler routing records into a Logger.
+type slogHandler struct {
+	logger Logger
+	fields []Field
+	prefix string
+}
+
+// NewSlogHandler create a slog.Handler which routes the records of
+// slog.Logger into the provided Logger, so that code using slog logs via this
+// package. slog levels are mapped by SlogLevel. Attributes become fields, and
+// the attributes in groups become fields keyed by the group names and the
+// attribute key joined with `.`, i.e. `req.method`. Attributes added by
+// WithAttrs and groups opened by WithGroup are carried by the returned
+// handlers.
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+// SlogLevel maps a slog.Level to the Level: levels from slog.LevelError up are
+// ErrorLevel, from slog.LevelWarn are WarnLevel, from slog.LevelInfo are
+// InfoLevel, from slog.LevelDebug are DebugLevel, and the lower are
+// TraceLevel.
+func SlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ErrorLevel
+	case level >= slog.LevelWarn:
+		return WarnLevel
+	case level >= slog.LevelInfo:
+		return InfoLevel
+	case level >= slog.LevelDebug:
+		return DebugLevel
+	default:
+		return TraceLevel
+	}
+}
+
+// ToSlogLevel maps a Level to a slog.Level, as 4 times of the Level, so that
+// the predefined Levels are mapped to the slog levels of the same names, i.e.
+// DebugLevel to slog.LevelDebug. It is the inverse of SlogLevel for them.
+func ToSlogLevel(level Level) slog.Level {
+	return slog.Level(4 * int(level))
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(ctx, SlogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := h.fields
+	if r.NumAttrs() > 0 {
+		fields = append(make([]Field, 0, len(fields)+r.NumAttrs()), fields...)
+		r.Attrs(func(a slog.Attr) bool {
+			fields = appendSlogAttr(fields, h.prefix, a)
+			return true
+		})
+	}
+	h.logger.AtLevel(ctx, SlogLevel(r.Level)).WithFields(fields...).Print(r.Message)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := append(make([]Field, 0, len(h.fields)+len(attrs)), h.fields...)
+	for _, a := range attrs {
+		fields = appendSlogAttr(fields, h.prefix, a)
+	}
+	return &slogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &slogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// appendSlogAttr appends the attribute to fields as fields keyed with prefix.
+// Groups are flattened, and empty attributes are ignored.
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return append(fields, Field{prefix + a.Key, a.Value.Any()})
+	}
+	if len(a.Key) > 0 {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		fields = appendSlogAttr(fields, prefix, ga)
+	}
+	return fields
+}
+
+// slogOutPutter is an OutPutter handling records with a slog.Handler.
+type slogOutPutter struct {
+	handler slog.Handler
+}
+
+// NewSlogOutPutter create a OutPutter which handles records with the
+// provided slog.Handler, so that an existing slog backend is reused. The
+// Level is mapped by ToSlogLevel, records not enabled to the handler are
+// skipped, and Valuers are resolved before building the attributes.
+// The logger name is surfaced as the attribute keyed LoggerKey, carried by
+// the fields from the builtin Logger; the LevelKey field is left out as the
+// level is the one of the slog.Record. The source of the record is available
+// to the handler, i.e. with slog.HandlerOptions.AddSource.
+func NewSlogOutPutter(h slog.Handler) OutPutter {
+	return &slogOutPutter{handler: h}
+}
+
+func (s *slogOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sl := ToSlogLevel(level)
+	if !s.handler.Enabled(ctx, sl) {
+		return
+	}
+	var pcs [1]uintptr
+	// skip Callers and OutPut, the rest like the std OutPutter
+	runtime.Callers(callDepth+3, pcs[:])
+	r := slog.NewRecord(clockNow(), sl, msg, pcs[0])
+	for _, field := range fields {
+		if len(field.Key) == 0 || field.Key == LevelKey {
+			continue
+		}
+		r.AddAttrs(slog.Any(field.Key, NamedValue(ctx, name, field.Value)))
+	}
+	_ = s.handler.Handle(ctx, r)
+}
+
+func (s *slogOutPutter) Describe() string {
+	return "slog"
+}
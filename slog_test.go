@@ -0,0 +1,94 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewSlogHandler(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(buildStdLogger("slog", w)))
+	logger.Debug("hidden")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+	logger.With("app", "demo").WithGroup("req").
+		Warn("abc", "method", "GET", slog.Group("user", "id", 1), slog.Attr{})
+	expect := "level=WARN logger=slog app=demo req.method=GET req.user.id=1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := map[slog.Level]Level{
+		slog.LevelDebug - 1: TraceLevel,
+		slog.LevelDebug:     DebugLevel,
+		slog.LevelInfo:      InfoLevel,
+		slog.LevelInfo + 2:  InfoLevel,
+		slog.LevelWarn:      WarnLevel,
+		slog.LevelError:     ErrorLevel,
+		slog.LevelError + 4: ErrorLevel,
+	}
+	for level, expect := range tests {
+		if got := SlogLevel(level); got != expect {
+			t.Errorf("%v: expect %v, got %v", level, expect, got)
+		}
+	}
+}
+
+func TestToSlogLevel(t *testing.T) {
+	tests := map[Level]slog.Level{
+		TraceLevel: slog.LevelDebug - 4,
+		DebugLevel: slog.LevelDebug,
+		InfoLevel:  slog.LevelInfo,
+		WarnLevel:  slog.LevelWarn,
+		ErrorLevel: slog.LevelError,
+	}
+	for level, expect := range tests {
+		if got := ToSlogLevel(level); got != expect {
+			t.Errorf("%v: expect %v, got %v", level, expect, got)
+		}
+		if got := SlogLevel(ToSlogLevel(level)); got != level {
+			t.Errorf("%v: expect round trip, got %v", level, got)
+		}
+	}
+}
+
+func TestNewSlogOutPutter(t *testing.T) {
+	w := &bytes.Buffer{}
+	h := slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := NewStdLogger("slog", NewSlogOutPutter(h))
+	ctx := context.Background()
+	logger.AtLevel(ctx, InfoLevel).Print("hidden")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+	user := Valuer(func(ctx context.Context) interface{} { return "mike" })
+	logger.AtLevel(ctx, ErrorLevel).With("user", user).Print("abc")
+	expect := "level=ERROR msg=abc logger=slog user=mike\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+
+	w.Reset()
+	h = slog.NewTextHandler(w, &slog.HandlerOptions{AddSource: true})
+	NewStdLogger("slog", NewSlogOutPutter(h)).AtLevel(ctx, InfoLevel).Print("abc")
+	if got := w.String(); !strings.Contains(got, "slog_test.go:") {
+		t.Errorf("expect source of the caller, got %q", got)
+	}
+}
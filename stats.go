@@ -0,0 +1,71 @@
+package log
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	ua "go.uber.org/atomic"
+)
+
+// StatsMessage is the message of the lines logged by the stats reporter.
+// See StartStatsReporter.
+const StatsMessage = "logging stats"
+
+// _levelCounts counts the records emitted by the builtin Printers per Level,
+// indexed by the Level offset from math.MinInt8.
+var _levelCounts [math.MaxUint8 + 1]ua.Int64
+
+// countRecord counts a record emitted at level.
+func countRecord(level Level) {
+	_levelCounts[int(level)-math.MinInt8].Inc()
+}
+
+// StartStatsReporter starts a goroutine which logs, every interval, the
+// number of records emitted by the builtin Printers per Level since the last
+// report, via the provided Logger at InfoLevel. The counts are fields keyed by
+// the Level names, levels without records are omitted, and the stats lines
+// are counted too. No line is logged for an interval without records.
+// Intervals are measured by the registered Clock (see UseClock).
+// The returned function stops the reporter.
+func StartStatsReporter(interval time.Duration, logger Logger) (stop func()) {
+	ticker := getClock().NewTicker(interval)
+	done := make(chan struct{})
+	var last [len(_levelCounts)]int64
+	for i := range _levelCounts {
+		last[i] = _levelCounts[i].Load()
+	}
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C():
+			}
+			var fields []Field
+			for i := range _levelCounts {
+				count := _levelCounts[i].Load()
+				if delta := count - last[i]; delta > 0 {
+					fields = append(fields, Field{Level(i + math.MinInt8).String(), delta})
+				}
+				last[i] = count
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			p := logger.AtLevel(context.Background(), InfoLevel)
+			for _, field := range fields {
+				p = p.With(field.Key, field.Value)
+			}
+			p.Print(StatsMessage)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"context"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// chanOutPutter sends the output lines to a channel.
+type chanOutPutter chan Record
+
+func (c chanOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, _ int) {
+	c <- Record{Name: name, Level: level, Msg: msg, Fields: fields}
+}
+
+func TestStartStatsReporter(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	out := make(chanOutPutter, 16)
+	stop := StartStatsReporter(time.Minute, NewStdLogger("stats", out))
+	defer stop()
+	l := NewStdLogger("app", NewStdOutPutter(log.New(io.Discard, "", 0)))
+	ctx := context.Background()
+	l.AtLevel(ctx, InfoLevel).Print("a")
+	l.AtLevel(ctx, InfoLevel).Print("b")
+	l.AtLevel(ctx, WarnLevel).Print("c")
+	clock.tick(time.Minute)
+	rec := <-out
+	expect := []Field{{LevelKey, InfoLevel}, {LoggerKey, "stats"}, {"INFO", int64(2)}, {"WARN", int64(1)}}
+	if rec.Msg != StatsMessage || !reflect.DeepEqual(rec.Fields, expect) {
+		t.Errorf("expect %v, got %v %v", expect, rec.Msg, rec.Fields)
+	}
+	l.AtLevel(ctx, ErrorLevel).Print("d")
+	clock.tick(time.Minute)
+	rec = <-out
+	// the previous stats line is counted too
+	expect = []Field{{LevelKey, InfoLevel}, {LoggerKey, "stats"}, {"INFO", int64(1)}, {"ERROR", int64(1)}}
+	if !reflect.DeepEqual(rec.Fields, expect) {
+		t.Errorf("expect %v, got %v", expect, rec.Fields)
+	}
+	stop()
+	stop()
+}
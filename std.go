@@ -3,10 +3,20 @@ package log
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 	"unsafe"
 
 	ua "go.uber.org/atomic"
@@ -18,6 +28,27 @@ const (
 	LevelKey = "level"
 	// LoggerKey is field key for logger name.
 	LoggerKey = "logger"
+	// RunIDKey is field key for the process run ID. See RunID.
+	RunIDKey = "run_id"
+	// MsgTruncatedKey is field key for marking the message is truncated.
+	MsgTruncatedKey = "msg_truncated"
+	// MsgFullKey is field key for the full text of a multiline message.
+	MsgFullKey = "msg.full"
+	// EventKey is field key for event name. See Printer.Event.
+	EventKey = "event"
+	// LevelThresholdKey is field key for the lowest Level the Logger enabled.
+	LevelThresholdKey = "level_threshold"
+	// TimestampKey is field key for the time a record is printed.
+	TimestampKey = "ts"
+	// CallerKey is field key for the `file:line` printing a record.
+	CallerKey = "caller"
+	// ErrorFieldKey is field key for error. See Printer.WithError.
+	ErrorFieldKey = "error"
+	// DurationKey is field key for elapsed time. See Timer.
+	DurationKey = "duration"
+	// DefaultLabelPrefix is the default key prefix of labels. See
+	// Printer.WithLabels.
+	DefaultLabelPrefix = "label."
 )
 
 // Field represent a key/value pair.
@@ -32,9 +63,16 @@ type Field struct {
 
 // OutPutter is the real final output of builtin standard Logger and Printer.
 // It calls the underlying logging / printing infrastructures.
+// The fields slice passed to OutPut is owned by the caller and may be shared,
+// i.e. fanned out to multiple OutPutters. An OutPutter must not alter it; one
+// needs to modify fields should work on a copy (see withFieldsCopy).
 type OutPutter interface {
 	// OutPut output msg, fields at a specific level to the underlying
 	// logging / printing infrastructures.
+	// The code calling the Print method of the Printer is callDepth+1 frames
+	// above the caller of OutPut, for the caller information. An OutPutter
+	// wrapping another one calls it with callDepth+1, for its own OutPut
+	// frame.
 	OutPut(ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int)
 }
 
@@ -42,51 +80,633 @@ type OutPutter interface {
 type stdOutPutter struct {
 	out     *log.Logger
 	bufPool *sync.Pool
+
+	numericLevel bool
+	levelNumber  bool
+	lowerLevel   bool
+	shortLevel   bool
+	durEncoder   DurationEncoder
+	maxMsgLen    int
+	msgSummary   bool
+	stable       bool
+	interpolate  bool
+	collapse     bool
+	valuerLimit  time.Duration
+	bufInitCap   int
+	typedValues  bool
+	levelField   LevelField
+	errHandler   func(err error)
+	logfmt       bool
+}
+
+// StdOutPutterOption is option for the OutPutter created by NewStdOutPutter.
+type StdOutPutterOption func(s *stdOutPutter)
+
+// WithNumericLevel make the OutPutter render Level values as numbers, i.e.
+// `level=0` instead of `level=INFO`.
+func WithNumericLevel(numeric bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.numericLevel = numeric
+	}
+}
+
+// WithLevelNumber make the OutPutter render Level values as both name and
+// number. The number is rendered as an extra field with key suffixed by
+// `_num`, i.e. `level=INFO level_num=0`.
+func WithLevelNumber(dual bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.levelNumber = dual
+	}
+}
+
+// WithLowercaseLevel make the OutPutter render Level names in lower case,
+// i.e. `level=info` instead of `level=INFO`. The registered level names are
+// not affected.
+func WithLowercaseLevel(lower bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.lowerLevel = lower
+	}
+}
+
+// WithShortLevel make the OutPutter render Level names as their first
+// letter in upper case, i.e. `level=I` instead of `level=INFO`. Levels without
+// registered name are rendered as `?`.
+func WithShortLevel(short bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.shortLevel = short
+	}
+}
+
+// DurationEncoder decides how the OutPutter renders time.Duration values.
+type DurationEncoder int
+
+// Define DurationEncoders.
+const (
+	// DurationString renders a time.Duration as its String method, i.e. `1h2m3s`.
+	DurationString DurationEncoder = iota
+	// DurationSeconds renders a time.Duration as float seconds, i.e. `1.5`.
+	DurationSeconds
+	// DurationMillis renders a time.Duration as integer milliseconds.
+	DurationMillis
+	// DurationNanos renders a time.Duration as integer nanoseconds.
+	DurationNanos
+)
+
+// WithDurationEncoder make the OutPutter render any time.Duration value with
+// the provided DurationEncoder. Default is DurationString.
+func WithDurationEncoder(encoder DurationEncoder) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.durEncoder = encoder
+	}
+}
+
+// WithMaxMessageLen make the OutPutter truncate messages longer than n bytes.
+// The truncation never splits a multibyte character, and is marked by an
+// ellipsis and a `msg_truncated=true` field. Zero or negative n means
+// unlimited, which is the default.
+func WithMaxMessageLen(n int) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.maxMsgLen = n
+	}
+}
+
+// WithMultilineSummary make the OutPutter use the first line of a multiline
+// message as the message, and output the full text as a field keyed
+// MsgFullKey. Default is off.
+func WithMultilineSummary(summary bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.msgSummary = summary
+	}
+}
+
+// WithStableOutput make the OutPutter output deterministic lines regardless
+// of the order the fields were accumulated: the level and logger fields are
+// pinned first, other fields follow in the order of their keys, and the
+// message is the last.
+func WithStableOutput(stable bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.stable = stable
+	}
+}
+
+// WithSortedFields make the OutPutter output fields sorted by key, for log
+// diffing and golden tests. It is the same as WithStableOutput: the level and
+// logger fields stay first and the message stays last.
+func WithSortedFields(sorted bool) StdOutPutterOption {
+	return WithStableOutput(sorted)
+}
+
+// WithInterpolateTemplates make the OutPutter replace `{key}` tokens in the
+// message with the resolved value of the field keyed key, like structured
+// message templates. The fields are still output as usual. Tokens without a
+// matching field stay literal. Default is off.
+func WithInterpolateTemplates(interpolate bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.interpolate = interpolate
+	}
+}
+
+// WithCollapseDuplicateFields make the OutPutter skip a field if the field
+// output immediately before it has the same key and resolved value, i.e. when
+// a value is set by both the global and the context layers. Fields with
+// values not comparable are never skipped. Default is off.
+func WithCollapseDuplicateFields(collapse bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.collapse = collapse
+	}
+}
+
+// ValuerTimeoutPlaceholder is the value output in place of the value of a
+// Valuer which can't be resolved in time. See WithValuerTimeout.
+const ValuerTimeoutPlaceholder = "<timeout>"
+
+// WithValuerTimeout make the OutPutter resolve each Valuer or NameValuer in a
+// goroutine, and output ValuerTimeoutPlaceholder in place of the value if it
+// is not resolved in d. It protects the logging goroutine from slow Valuers,
+// i.e. ones doing I/O, at the cost of a goroutine per resolving.
+// Note that the goroutine of a Valuer timed out is not stopped: it keeps
+// running until the Valuer returns, and leaks forever if it never returns.
+// Zero or negative d means no timeout, which is the default.
+func WithValuerTimeout(d time.Duration) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.valuerLimit = d
+	}
+}
+
+// WithBufferInitialCap make the OutPutter pre-size its line buffers to n
+// bytes, reducing the reallocations of growing the buffers for typical line
+// lengths, i.e. 256. Default is 0, buffers start empty.
+func WithBufferInitialCap(n int) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.bufInitCap = n
+	}
+}
+
+// WithTypedTextValues make the OutPutter quote values which are not bools,
+// numbers or nil, i.e. `key="abc"` and `key="123"` for strings but `key=123`
+// for an int, so that the output is unambiguously typed for the tools
+// re-parsing it. Default is off.
+func WithTypedTextValues(typed bool) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.typedValues = typed
+	}
+}
+
+// LevelField decides how the level field is keyed and valued. See
+// WithLevelField.
+type LevelField struct {
+	// Key replaces LevelKey as the key of the level field, if not empty.
+	Key string
+	// Encoder maps the Level to the value output, if not nil.
+	Encoder func(Level) interface{}
+}
+
+// WithLevelField make the OutPutter key and value the field keyed LevelKey
+// holding a Level as the LevelField decides, i.e. to output ErrorLevel as
+// `priority=high` for backends expecting their own severities. If an Encoder
+// is provided, the other options on Level rendering don't apply.
+func WithLevelField(field LevelField) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.levelField = field
+	}
+}
+
+// WithErrorHandler make the OutPutter pass the errors writing lines, i.e.
+// failures of a file or network sink, to handler. By default, or if nil is
+// passed, they are passed to the handler registered by
+// SetInternalErrorHandler.
+func WithErrorHandler(handler func(err error)) StdOutPutterOption {
+	return func(s *stdOutPutter) {
+		s.errHandler = handler
+	}
 }
 
 // NewStdOutPutter create a OutPutter based on Go SDK log.Logger.
 // It output to the provided log.Logger.
+// Fields are output in the order they are passed, which for the builtin
+// Printer is the order they were first added, after the level and logger
+// fields; the message is output last. WithStableOutput sorts the fields
+// instead.
 // If nil is passed to the function, log.Default() will be called to get a
 // log.Logger.
-func NewStdOutPutter(out *log.Logger) OutPutter {
+func NewStdOutPutter(out *log.Logger, opts ...StdOutPutterOption) OutPutter {
 	if out == nil {
 		out = log.Default()
 	}
-	return &stdOutPutter{
+	s := &stdOutPutter{
 		out: out,
-		bufPool: &sync.Pool{
-			New: func() interface{} {
-				return &bytes.Buffer{}
-			},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.bufPool = &sync.Pool{
+		New: func() interface{} {
+			if s.bufInitCap > 0 {
+				return bytes.NewBuffer(make([]byte, 0, s.bufInitCap))
+			}
+			return &bytes.Buffer{}
 		},
 	}
+	return s
 }
 
-func (s *stdOutPutter) OutPut(ctx context.Context, _ string, _ Level, msg string, fields []Field, callDepth int) {
+// NewLogfmtOutPutter create a OutPutter based on Go SDK log.Logger like
+// NewStdOutPutter, but outputs logfmt lines: the message is output as a field
+// keyed `msg`, and values containing spaces, quotes, equals signs or control
+// characters, or empty, are quoted and escaped, so that lines can be parsed
+// back. Simple tokens stay unquoted for readability. With
+// WithTypedTextValues, values are quoted as that option decides.
+func NewLogfmtOutPutter(out *log.Logger, opts ...StdOutPutterOption) OutPutter {
+	s := NewStdOutPutter(out, opts...).(*stdOutPutter)
+	s.logfmt = true
+	return s
+}
+
+func (s *stdOutPutter) OutPut(ctx context.Context, name string, _ Level, msg string, fields []Field, callDepth int) {
 	buf := s.bufPool.Get().(*bytes.Buffer)
 	defer func() {
 		buf.Reset()
 		s.bufPool.Put(buf)
 	}()
+	if s.stable {
+		fields = stableFields(fields)
+	}
+	var resolved map[string]interface{}
+	if s.interpolate && strings.IndexByte(msg, '{') >= 0 {
+		resolved = make(map[string]interface{}, len(fields))
+	}
+	var prevKey string
+	var prevValue interface{}
 	for _, field := range fields {
 		if len(field.Key) == 0 {
 			continue
 		}
-		_, _ = fmt.Fprintf(buf, "%s=%v ", field.Key, Value(ctx, field.Value))
+		value := s.resolve(ctx, name, field.Value)
+		if resolved != nil {
+			resolved[field.Key] = value
+		}
+		if s.collapse {
+			if prevKey == field.Key && sameValue(prevValue, value) {
+				continue
+			}
+			prevKey, prevValue = field.Key, value
+		}
+		s.writeField(ctx, buf, field.Key, value)
+	}
+	if resolved != nil {
+		msg = s.interpolateMessage(ctx, msg, resolved)
+	}
+	if i := strings.IndexByte(msg, '\n'); s.msgSummary && i >= 0 {
+		s.writeField(ctx, buf, MsgFullKey, msg)
+		msg = strings.TrimSuffix(msg[:i], "\r")
+	}
+	if truncated, ok := truncateMessage(msg, s.maxMsgLen); ok {
+		msg = truncated
+		s.writeField(ctx, buf, MsgTruncatedKey, true)
+	}
+	if s.logfmt {
+		buf.WriteString("msg=")
+		start := buf.Len()
+		buf.WriteString(msg)
+		s.quoteFrom(buf, start)
+	} else {
+		_, _ = fmt.Fprint(buf, msg)
+	}
+	if err := s.out.Output(callDepth+3, buf.String()); err != nil {
+		if s.errHandler != nil {
+			s.errHandler(err)
+			return
+		}
+		handleInternalError(err)
+	}
+}
+
+func (s *stdOutPutter) Describe() string {
+	if s.logfmt {
+		return "logfmt"
+	}
+	return "std"
+}
+
+// writeField writes the key and resolved value to buf, followed by a space.
+func (s *stdOutPutter) writeField(ctx context.Context, buf *bytes.Buffer, key string, value interface{}) {
+	switch v := value.(type) {
+	case flagValue:
+		_, _ = fmt.Fprintf(buf, "%s ", key)
+	case Level:
+		if key == LevelKey {
+			if len(s.levelField.Key) > 0 {
+				key = s.levelField.Key
+			}
+			if s.levelField.Encoder != nil {
+				_, _ = fmt.Fprintf(buf, "%s=", key)
+				s.writeValue(ctx, buf, s.levelField.Encoder(v))
+				buf.WriteByte(' ')
+				return
+			}
+		}
+		switch {
+		case s.numericLevel:
+			_, _ = fmt.Fprintf(buf, "%s=%d ", key, v)
+		case s.shortLevel:
+			_, _ = fmt.Fprintf(buf, "%s=%s ", key, shortLevelName(v))
+		case s.lowerLevel:
+			_, _ = fmt.Fprintf(buf, "%s=%s ", key, strings.ToLower(v.String()))
+		default:
+			_, _ = fmt.Fprintf(buf, "%s=%s ", key, v)
+		}
+		if s.levelNumber {
+			_, _ = fmt.Fprintf(buf, "%s_num=%d ", key, v)
+		}
+	default:
+		_, _ = fmt.Fprintf(buf, "%s=", key)
+		start := buf.Len()
+		s.writeValue(ctx, buf, value)
+		s.quoteFrom(buf, start)
+		buf.WriteByte(' ')
+	}
+}
+
+// quoteFrom quotes the text written to buf from start, if it is a logfmt
+// OutPutter and the text needs quoting.
+func (s *stdOutPutter) quoteFrom(buf *bytes.Buffer, start int) {
+	if !s.logfmt || s.typedValues || !needsQuoting(buf.Bytes()[start:]) {
+		return
+	}
+	text := string(buf.Bytes()[start:])
+	buf.Truncate(start)
+	buf.WriteString(strconv.Quote(text))
+}
+
+// needsQuoting reports whether a logfmt value needs quoting.
+func needsQuoting(text []byte) bool {
+	if len(text) == 0 {
+		return true
+	}
+	for _, b := range text {
+		if b <= ' ' || b == '=' || b == '"' || b == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve resolves v by NamedValue, within the Valuer timeout if set. The
+// elements of accumulated values are resolved one by one.
+func (s *stdOutPutter) resolve(ctx context.Context, name string, v interface{}) interface{} {
+	if values, ok := v.(fieldValues); ok {
+		resolved := make(fieldValues, len(values))
+		for i, e := range values {
+			resolved[i] = s.resolve(ctx, name, e)
+		}
+		return resolved
+	}
+	if s.valuerLimit <= 0 {
+		return NamedValue(ctx, name, v)
+	}
+	switch v.(type) {
+	case Valuer, NameValuer, SimpleValuer, func() interface{}:
+	default:
+		return v
+	}
+	ch := make(chan interface{}, 1)
+	go func() {
+		ch <- NamedValue(ctx, name, v)
+	}()
+	timer := time.NewTimer(s.valuerLimit)
+	defer timer.Stop()
+	select {
+	case value := <-ch:
+		return value
+	case <-timer.C:
+		return ValuerTimeoutPlaceholder
+	}
+}
+
+// interpolateMessage replaces `{key}` tokens in msg with the rendered value
+// of the resolved field keyed key. Unmatched tokens are kept.
+func (s *stdOutPutter) interpolateMessage(ctx context.Context, msg string, resolved map[string]interface{}) string {
+	out := &bytes.Buffer{}
+	for {
+		end := strings.IndexByte(msg, '}')
+		if end < 0 {
+			break
+		}
+		start := strings.LastIndexByte(msg[:end], '{')
+		if start < 0 {
+			out.WriteString(msg[:end+1])
+			msg = msg[end+1:]
+			continue
+		}
+		out.WriteString(msg[:start])
+		if value, ok := resolved[msg[start+1:end]]; ok {
+			s.writeValue(ctx, out, value)
+		} else {
+			out.WriteString(msg[start : end+1])
+		}
+		msg = msg[end+1:]
+	}
+	out.WriteString(msg)
+	return out.String()
+}
+
+// sameValue reports whether a and b are equal comparable values.
+func sameValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// stableFields returns a copy of fields with the level and logger fields
+// first, followed by other fields sorted by key.
+func stableFields(fields []Field) []Field {
+	rank := func(key string) int {
+		switch key {
+		case LevelKey:
+			return 0
+		case LoggerKey:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sorted := withFieldsCopy(fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i].Key), rank(sorted[j].Key)
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// truncateMessage truncates msg to at most max bytes at a rune boundary and
+// appends an ellipsis. It reports whether msg is truncated.
+func truncateMessage(msg string, max int) (string, bool) {
+	if max <= 0 || len(msg) <= max {
+		return msg, false
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + "…", true
+}
+
+// shortLevelName returns the first letter of the level name in upper case,
+// or `?` if the level has no registered name.
+func shortLevelName(level Level) string {
+	name, ok := levelName(level)
+	if !ok {
+		return "?"
+	}
+	r, _ := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r))
+}
+
+// writeValue writes the resolved value to buf. See resolve.
+func (s *stdOutPutter) writeValue(ctx context.Context, buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case fieldValues:
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			s.writeValue(ctx, buf, e)
+		}
+	case int:
+		var scratch [32]byte
+		buf.Write(strconv.AppendInt(scratch[:0], int64(v), 10))
+	case int64:
+		var scratch [32]byte
+		buf.Write(strconv.AppendInt(scratch[:0], v, 10))
+	case uint64:
+		var scratch [32]byte
+		buf.Write(strconv.AppendUint(scratch[:0], v, 10))
+	case float64:
+		var scratch [32]byte
+		buf.Write(strconv.AppendFloat(scratch[:0], v, 'g', -1, 64))
+	case json.RawMessage:
+		buf.Write(v)
+	case time.Duration:
+		switch s.durEncoder {
+		case DurationSeconds:
+			_, _ = fmt.Fprint(buf, v.Seconds())
+		case DurationMillis:
+			_, _ = fmt.Fprint(buf, v.Milliseconds())
+		case DurationNanos:
+			_, _ = fmt.Fprint(buf, v.Nanoseconds())
+		default:
+			s.writeText(buf, v.String())
+		}
+	case string:
+		s.writeText(buf, v)
+	default:
+		if !s.typedValues || isBareValue(v) {
+			_, _ = fmt.Fprint(buf, v)
+			return
+		}
+		s.writeText(buf, fmt.Sprint(v))
+	}
+}
+
+// isBareValue reports whether v is a bool, number or nil, which are rendered
+// without quotes even if typed text values is on.
+func isBareValue(v interface{}) bool {
+	switch v.(type) {
+	case nil, bool:
+		return true
+	case fmt.Stringer, error, fmt.Formatter:
+		return false
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.Bool:
+		return true
+	default:
+		return false
 	}
-	_, _ = fmt.Fprint(buf, msg)
-	_ = s.out.Output(callDepth+3, buf.String())
+}
+
+// writeText writes a text value to buf, quoted if typed text values is on.
+func (s *stdOutPutter) writeText(buf *bytes.Buffer, text string) {
+	if !s.typedValues {
+		buf.WriteString(text)
+		return
+	}
+	var scratch [64]byte
+	buf.Write(strconv.AppendQuote(scratch[:0], text))
 }
 
 // ======== OutPutFilter =========
 
 var _ OutPutter = &OutPutFilter{}
 
-// OutPutFilter is a filter wrapped a OutPutter.
+// OutPutFilter is a filter wrapped a OutPutter. It may hold several enable
+// checking functions and field modifiers, see NewOutPutFilter.
 type OutPutFilter struct {
-	underlying      OutPutter
-	enableFunc      func(ctx context.Context, name string, level Level) bool
-	fieldModifyFunc func(ctx context.Context, field *Field)
+	underlying     OutPutter
+	enableFuncs    []func(ctx context.Context, name string, level Level) bool
+	fieldModifiers []func(ctx context.Context, name string, field *Field)
+	fieldsFunc     func(ctx context.Context, fields []Field) []Field
+	desc           string
+}
+
+// OutPutFilterOption is option for the OutPutFilter created by
+// NewOutPutFilter.
+type OutPutFilterOption func(o *OutPutFilter)
+
+// WithEnable add an enable checking function to the filter. The output will
+// be skipped if any of the enable checking functions return false. They are
+// called in the order they were added.
+func WithEnable(f func(ctx context.Context, name string, level Level) bool) OutPutFilterOption {
+	return func(o *OutPutFilter) {
+		if f != nil {
+			o.enableFuncs = append(o.enableFuncs, f)
+		}
+	}
+}
+
+// WithFieldModifier add a field modifier to the filter. Each field is passed
+// to the field modifiers in the order they were added; a field modifier may
+// change the key or the value of the field, or skip the field by setting
+// the key empty, which is not passed to the rest field modifiers then. The
+// fields of the caller are never modified.
+func WithFieldModifier(f func(ctx context.Context, name string, field *Field)) OutPutFilterOption {
+	return func(o *OutPutFilter) {
+		if f != nil {
+			o.fieldModifiers = append(o.fieldModifiers, f)
+		}
+	}
+}
+
+// WithFilterDescription set the description of the filter, see Describe.
+func WithFilterDescription(desc string) OutPutFilterOption {
+	return func(o *OutPutFilter) {
+		o.desc = desc
+	}
+}
+
+// NewOutPutFilter build a OutPutFilter wrapping the provided OutPutter with
+// the options. A single filter holding several enable checking functions and
+// field modifiers is cheaper than stacked filters each holding one: the
+// fields are copied and iterated once.
+func NewOutPutFilter(o OutPutter, opts ...OutPutFilterOption) OutPutter {
+	if o == nil {
+		return o
+	}
+	f := &OutPutFilter{underlying: o}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 // OutPut do checking and modification before calling the OutPut() method of the wrapped OutPutter.
@@ -95,60 +715,235 @@ func (o *OutPutFilter) OutPut(
 	if o.underlying == nil {
 		return
 	}
-	if o.enableFunc != nil && !o.enableFunc(ctx, name, level) {
-		return
+	for _, enable := range o.enableFuncs {
+		if !enable(ctx, name, level) {
+			return
+		}
+	}
+	if len(o.fieldModifiers) > 0 {
+		fields = withFieldsCopy(fields)
+		for i := 0; i < len(fields); i++ {
+			for _, modify := range o.fieldModifiers {
+				if len(fields[i].Key) == 0 {
+					break
+				}
+				modify(ctx, name, &fields[i])
+			}
+		}
 	}
-	for i := 0; i < len(fields); i++ {
-		o.fieldModifyFunc(ctx, &fields[i])
+	if o.fieldsFunc != nil {
+		fields = o.fieldsFunc(ctx, fields)
 	}
 	o.underlying.OutPut(ctx, name, level, msg, fields, callDepth+1)
 }
 
+// Describe describes the filter.
+func (o *OutPutFilter) Describe() string {
+	if len(o.desc) == 0 {
+		return "filter"
+	}
+	return o.desc
+}
+
+// Unwrap returns the wrapped OutPutter.
+func (o *OutPutFilter) Unwrap() OutPutter {
+	return o.underlying
+}
+
+// withFieldsCopy returns a copy of fields. Filters mutating fields must
+// mutate the copy rather than the slice of the caller.
+func withFieldsCopy(fields []Field) []Field {
+	if fields == nil {
+		return nil
+	}
+	cp := make([]Field, len(fields))
+	copy(cp, fields)
+	return cp
+}
+
 // FilterEnable build a OutPutFilter wrapping the provided OutPutter. The output
 // will be skipped if the enable checking function return false.
 func FilterEnable(o OutPutter, f func(ctx context.Context, name string, level Level) bool) OutPutter {
-	if o == nil {
-		return o
-	}
-	return &OutPutFilter{
-		underlying: o,
-		enableFunc: f,
-	}
+	return NewOutPutFilter(o, WithEnable(f), WithFilterDescription("enable"))
 }
 
 // FilterRemoveField build a OutPutFilter wrapping the provided OutPutter. Any field
 // with the specific name will be skipped.
 func FilterRemoveField(o OutPutter, name string) OutPutter {
-	if o == nil {
-		return o
-	}
-	return &OutPutFilter{
-		underlying: o,
-		fieldModifyFunc: func(ctx context.Context, field *Field) {
+	return NewOutPutFilter(o,
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
 			if field.Key == name {
 				field.Key = ""
 			}
-		},
-	}
+		}),
+		WithFilterDescription(fmt.Sprintf("remove(%s)", name)),
+	)
 }
 
 // FilterCoverField build a OutPutFilter wrapping the provided OutPutter.
 // The value of the field with specific name will be replaced.
 func FilterCoverField(o OutPutter, name string, replace interface{}) OutPutter {
+	return NewOutPutFilter(o,
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
+			if field.Key != name {
+				return
+			}
+			field.Value = replace
+		}),
+		WithFilterDescription(fmt.Sprintf("cover(%s)", name)),
+	)
+}
+
+// FilterRenameField build a OutPutFilter wrapping the provided OutPutter. Any
+// field with the key from is output with the key to instead, i.e. to map the
+// `logger` key to `source` for a downstream schema. It is a no-op for records
+// without the key from. If a record already has a field keyed to, it is not
+// overwritten: both fields are output, in their positions.
+// Only fields can be renamed, i.e. LevelKey, LoggerKey and those added to the
+// Printer. The message is not a field: the `msg` key of the logfmt OutPutter
+// (see NewLogfmtOutPutter) can't be renamed by this filter.
+func FilterRenameField(o OutPutter, from, to string) OutPutter {
+	return NewOutPutFilter(o,
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
+			if field.Key == from {
+				field.Key = to
+			}
+		}),
+		WithFilterDescription(fmt.Sprintf("rename(%s,%s)", from, to)),
+	)
+}
+
+// FilterRedactValue build a OutPutFilter wrapping the provided OutPutter. The
+// value of every field is resolved, and if it is a string or a fmt.Stringer,
+// the matches of re in its text are replaced by replacement, whatever the key
+// is, i.e. to redact credit card numbers or emails. The replacement may refer
+// to submatches like regexp.Regexp.ReplaceAllString. Values of other types
+// are never stringified, so that structured data is kept intact. The values
+// accumulated by Printer.Append are redacted one by one.
+func FilterRedactValue(o OutPutter, re *regexp.Regexp, replacement string) OutPutter {
+	if re == nil {
+		return o
+	}
+	redact := func(ctx context.Context, name string, value interface{}) interface{} {
+		value = NamedValue(ctx, name, value)
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = v
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			return value
+		}
+		if re.MatchString(text) {
+			return re.ReplaceAllString(text, replacement)
+		}
+		return value
+	}
+	return NewOutPutFilter(o,
+		WithFieldModifier(func(ctx context.Context, name string, field *Field) {
+			values, ok := field.Value.(fieldValues)
+			if !ok {
+				field.Value = redact(ctx, name, field.Value)
+				return
+			}
+			// the accumulated values may be shared, redact a copy
+			redacted := make(fieldValues, len(values))
+			for i, e := range values {
+				redacted[i] = redact(ctx, name, e)
+			}
+			field.Value = redacted
+		}),
+		WithFilterDescription(fmt.Sprintf("redact(%s)", re)),
+	)
+}
+
+// FilterAddFields build a OutPutFilter wrapping the provided OutPutter. The
+// provided fields are prepended to the fields of every record. Valuers in the
+// provided fields are resolved per record.
+func FilterAddFields(o OutPutter, fields ...Field) OutPutter {
 	if o == nil {
 		return o
 	}
+	added := make([]Field, len(fields))
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		added[i] = field
+		keys[i] = field.Key
+	}
 	return &OutPutFilter{
 		underlying: o,
-		fieldModifyFunc: func(ctx context.Context, field *Field) {
-			if field.Key != name {
-				return
-			}
-			field.Value = replace
+		fieldsFunc: func(ctx context.Context, fields []Field) []Field {
+			merged := make([]Field, 0, len(added)+len(fields))
+			merged = append(merged, added...)
+			return append(merged, fields...)
 		},
+		desc: fmt.Sprintf("add(%s)", strings.Join(keys, ",")),
 	}
 }
 
+// condOutPutter routes each record to one of two OutPutters by a condition.
+type condOutPutter struct {
+	cond      func(ctx context.Context) bool
+	matched   OutPutter
+	unmatched OutPutter
+}
+
+// FilterWhen build a OutPutter which routes each record to wrapped if cond
+// returns true for the context of the record, or to o otherwise. It is
+// usually used to apply a filter chain (e.g., redaction) built on o
+// conditionally, without rebuilding the logger.
+// A nil OutPutter discards the records routed to it.
+func FilterWhen(o OutPutter, cond func(ctx context.Context) bool, wrapped OutPutter) OutPutter {
+	if cond == nil {
+		return o
+	}
+	return &condOutPutter{
+		cond:      cond,
+		matched:   wrapped,
+		unmatched: o,
+	}
+}
+
+func (c *condOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	o := c.unmatched
+	if c.cond(ctx) {
+		o = c.matched
+	}
+	if o == nil {
+		return
+	}
+	o.OutPut(ctx, name, level, msg, fields, callDepth+1)
+}
+
+func (c *condOutPutter) Describe() string {
+	return fmt.Sprintf("when(%s | %s)", Describe(c.matched), Describe(c.unmatched))
+}
+
+// FilterMaskInProd build a OutPutter wrapping the provided OutPutter, which
+// replaces the values of the fields with the keys by mask, only for records
+// whose context isProd returns true for, i.e. to mask PII in production but
+// show it in development. The decision is made per record.
+func FilterMaskInProd(o OutPutter, isProd func(ctx context.Context) bool, keys []string, mask string) OutPutter {
+	if o == nil {
+		return o
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	masked := NewOutPutFilter(o,
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
+			if _, ok := set[field.Key]; ok {
+				field.Value = mask
+			}
+		}),
+		WithFilterDescription(fmt.Sprintf("mask(%s)", strings.Join(keys, ","))),
+	)
+	return FilterWhen(o, isProd, masked)
+}
+
 // ======== Printer =========
 
 var _ Printer = (*stdPrinter)(nil)
@@ -160,8 +955,19 @@ type stdPrinter struct {
 	fields  []Field
 	ctx     context.Context
 	bufPool *sync.Pool
+	metrics []Field
+	// tsIndex is the index of the timestamp field in fields, 0 if absent.
+	tsIndex int
+	// callerIndex is the index of the caller field in fields, 0 if absent.
+	callerIndex int
+	// silent is true for a Printer at a disabled FatalLevel, which prints
+	// nothing but still exits.
+	silent bool
 }
 
+// fatalShutdownTimeout bounds the Shutdown before exiting at FatalLevel.
+const fatalShutdownTimeout = 5 * time.Second
+
 func (p *stdPrinter) Print(v ...interface{}) {
 	buf := p.bufPool.Get().(*bytes.Buffer)
 	defer func() {
@@ -169,7 +975,19 @@ func (p *stdPrinter) Print(v ...interface{}) {
 		p.bufPool.Put(buf)
 	}()
 	_, _ = fmt.Fprint(buf, v...)
-	p.logger.output.OutPut(p.ctx, p.logger.name, p.level, buf.String(), p.fields, 0)
+	p.output(buf.String(), 0)
+}
+
+// depthPrinter is implemented by the builtin Printers, for helpers printing
+// on behalf of their callers, i.e. Timer.
+type depthPrinter interface {
+	// printDepth prints msg like Print, reporting the caller skip frames
+	// above the caller of printDepth.
+	printDepth(skip int, msg string)
+}
+
+func (p *stdPrinter) printDepth(skip int, msg string) {
+	p.output(msg, skip)
 }
 
 func (p *stdPrinter) Printf(format string, v ...interface{}) {
@@ -179,7 +997,7 @@ func (p *stdPrinter) Printf(format string, v ...interface{}) {
 		p.bufPool.Put(buf)
 	}()
 	_, _ = fmt.Fprintf(buf, format, v...)
-	p.logger.output.OutPut(p.ctx, p.logger.name, p.level, buf.String(), p.fields, 0)
+	p.output(buf.String(), 0)
 }
 
 func (p *stdPrinter) Println(v ...interface{}) {
@@ -188,15 +1006,72 @@ func (p *stdPrinter) Println(v ...interface{}) {
 		buf.Reset()
 		p.bufPool.Put(buf)
 	}()
-	buf.WriteString(fmt.Sprintln(v...))
-	buf.Truncate(buf.Len() - 1)
-	p.logger.output.OutPut(p.ctx, p.logger.name, p.level, buf.String(), p.fields, 0)
+	writeln(buf, p.logger.printlnSep, v)
+	p.output(buf.String(), 0)
+}
+
+// callerOf returns the `file:line` of the caller skip frames above the
+// caller of callerOf, with the file name only.
+func callerOf(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "???:0"
+	}
+	return filepath.Base(file) + ":" + strconv.Itoa(line)
+}
+
+// writeln writes operands to buf like fmt.Println without the trailing
+// newline. Operands are joined by sep if it is not nil.
+func writeln(buf *bytes.Buffer, sep *string, v []interface{}) {
+	if sep == nil {
+		buf.WriteString(fmt.Sprintln(v...))
+		buf.Truncate(buf.Len() - 1)
+		return
+	}
+	for i, e := range v {
+		if i > 0 {
+			buf.WriteString(*sep)
+		}
+		_, _ = fmt.Fprint(buf, e)
+	}
+}
+
+// output passes msg to the OutPutter. It must be called directly by the Print
+// methods for the call depth counting, with skip being the number of extra
+// frames between the Print method and the code to report as the caller.
+func (p *stdPrinter) output(msg string, skip int) {
+	if p.tsIndex > 0 {
+		p.fields[p.tsIndex].Value = p.logger.timestamp.format(clockNow())
+	}
+	if p.callerIndex > 0 {
+		// skip output and the Print method
+		p.fields[p.callerIndex].Value = callerOf(2 + skip)
+	}
+	if !p.silent {
+		p.logger.output.OutPut(p.ctx, p.logger.name, p.level, msg, p.fields, 1+skip)
+		countRecord(p.level)
+		for _, metric := range p.metrics {
+			countMetric(metric.Key, metric.Value.(int64))
+		}
+	}
+	if p.level == PanicLevel {
+		panic(msg)
+	}
+	if p.level == FatalLevel {
+		// exit only after OutPut returned, and the records queued by
+		// buffering OutPutters are flushed.
+		ctx, cancel := context.WithTimeout(context.Background(), fatalShutdownTimeout)
+		_ = Shutdown(ctx)
+		cancel()
+		exit(1)
+	}
 }
 
 func (p *stdPrinter) With(key string, value interface{}) Printer {
 	if len(key) == 0 {
 		return p
 	}
+	checkReservedKey(key)
 	for i := 0; i < len(p.fields); i++ {
 		if p.fields[i].Key == key {
 			p.fields[i].Value = value
@@ -207,6 +1082,210 @@ func (p *stdPrinter) With(key string, value interface{}) Printer {
 	return p
 }
 
+func (p *stdPrinter) WithFields(fields ...Field) Printer {
+	for _, field := range fields {
+		p.With(field.Key, field.Value)
+	}
+	return p
+}
+
+func (p *stdPrinter) Append(key string, value interface{}) Printer {
+	if len(key) == 0 {
+		return p
+	}
+	checkReservedKey(key)
+	for i := 0; i < len(p.fields); i++ {
+		if p.fields[i].Key != key {
+			continue
+		}
+		if values, ok := p.fields[i].Value.(fieldValues); ok {
+			p.fields[i].Value = append(values, value)
+		} else {
+			p.fields[i].Value = fieldValues{p.fields[i].Value, value}
+		}
+		return p
+	}
+	p.fields = append(p.fields, Field{key, value})
+	return p
+}
+
+func (p *stdPrinter) Count(metric string, delta int64) Printer {
+	if len(metric) == 0 {
+		return p
+	}
+	p.metrics = append(p.metrics, Field{metric, delta})
+	return p.With(metric, delta)
+}
+
+func (p *stdPrinter) Event(name string) Printer {
+	return p.With(EventKey, name)
+}
+
+func (p *stdPrinter) WithLabels(labels map[string]string) Printer {
+	prefix := DefaultLabelPrefix
+	if p.logger.labelPrefix != nil {
+		prefix = *p.logger.labelPrefix
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.With(prefix+name, labels[name])
+	}
+	return p
+}
+
+func (p *stdPrinter) WithValue(key string, value interface{}) Printer {
+	return p.withValue(key, value, 0)
+}
+
+// withValue expands value if it is a LogValuer and depth does not exceed
+// maxLogValueDepth, which guards against self-referencing values.
+func (p *stdPrinter) withValue(key string, value interface{}, depth int) Printer {
+	lv, ok := value.(LogValuer)
+	if _, isErr := value.(error); !ok || isErr || depth >= maxLogValueDepth {
+		return p.With(key, value)
+	}
+	for _, field := range lv.LogFields() {
+		if len(field.Key) == 0 {
+			continue
+		}
+		p.withValue(key+"."+field.Key, field.Value, depth+1)
+	}
+	return p
+}
+
+func (p *stdPrinter) WithError(err error) Printer {
+	if err == nil {
+		return p
+	}
+	p.With(ErrorFieldKey, err)
+	return p.WithFields(ErrorFields(err)...)
+}
+
+func (p *stdPrinter) WithFlag(key string, cond bool) Printer {
+	if !cond {
+		return p
+	}
+	flag := Flag(key)
+	return p.With(flag.Key, flag.Value)
+}
+
+// multiPrinter is a Printer emits once per its member Printers, each at its
+// own level. All members are got from the same stdLogger. Metrics are
+// recorded once by the multiPrinter rather than by each member.
+type multiPrinter struct {
+	printers []*stdPrinter
+	metrics  []Field
+}
+
+func (m *multiPrinter) Print(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	m.countMetrics()
+	for _, p := range m.printers {
+		p.output(msg, 0)
+	}
+}
+
+func (m *multiPrinter) Printf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	m.countMetrics()
+	for _, p := range m.printers {
+		p.output(msg, 0)
+	}
+}
+
+func (m *multiPrinter) Println(v ...interface{}) {
+	buf := &bytes.Buffer{}
+	writeln(buf, m.printers[0].logger.printlnSep, v)
+	msg := buf.String()
+	m.countMetrics()
+	for _, p := range m.printers {
+		p.output(msg, 0)
+	}
+}
+
+func (m *multiPrinter) printDepth(skip int, msg string) {
+	m.countMetrics()
+	for _, p := range m.printers {
+		p.output(msg, skip)
+	}
+}
+
+// countMetrics records the metrics once for the record emitted by all
+// members. It is called before the members output, which may exit.
+func (m *multiPrinter) countMetrics() {
+	for _, metric := range m.metrics {
+		countMetric(metric.Key, metric.Value.(int64))
+	}
+}
+
+func (m *multiPrinter) With(key string, value interface{}) Printer {
+	for _, p := range m.printers {
+		p.With(key, value)
+	}
+	return m
+}
+
+func (m *multiPrinter) WithFields(fields ...Field) Printer {
+	for _, p := range m.printers {
+		p.WithFields(fields...)
+	}
+	return m
+}
+
+func (m *multiPrinter) WithError(err error) Printer {
+	for _, p := range m.printers {
+		p.WithError(err)
+	}
+	return m
+}
+
+func (m *multiPrinter) WithFlag(key string, cond bool) Printer {
+	for _, p := range m.printers {
+		p.WithFlag(key, cond)
+	}
+	return m
+}
+
+func (m *multiPrinter) Append(key string, value interface{}) Printer {
+	for _, p := range m.printers {
+		p.Append(key, value)
+	}
+	return m
+}
+
+func (m *multiPrinter) Count(metric string, delta int64) Printer {
+	if len(metric) == 0 {
+		return m
+	}
+	m.metrics = append(m.metrics, Field{metric, delta})
+	return m.With(metric, delta)
+}
+
+func (m *multiPrinter) Event(name string) Printer {
+	for _, p := range m.printers {
+		p.Event(name)
+	}
+	return m
+}
+
+func (m *multiPrinter) WithLabels(labels map[string]string) Printer {
+	for _, p := range m.printers {
+		p.WithLabels(labels)
+	}
+	return m
+}
+
+func (m *multiPrinter) WithValue(key string, value interface{}) Printer {
+	for _, p := range m.printers {
+		p.WithValue(key, value)
+	}
+	return m
+}
+
 // ======== Logger =========
 
 var _ Logger = (*stdLogger)(nil)
@@ -215,50 +1294,249 @@ var _ Logger = (*stdLogger)(nil)
 type stdLogger struct {
 	output OutPutter
 	name   string
+
+	printlnSep  *string
+	labelPrefix *string
+	runID       bool
+	threshold   bool
+	timestamp   TimestampFormat
+	caller      bool
+	levelStore  LevelStore
+}
+
+// StdLoggerOption is option for the Logger created by NewStdLogger.
+type StdLoggerOption func(l *stdLogger)
+
+// WithPrintlnSeparator set the separator the Println method of Printers
+// uses to join operands. By default, a space is always added between operands,
+// just like fmt.Println.
+func WithPrintlnSeparator(sep string) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.printlnSep = &sep
+	}
+}
+
+// WithLabelPrefix set the key prefix of labels added via Printer.WithLabels.
+// Default is DefaultLabelPrefix.
+func WithLabelPrefix(prefix string) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.labelPrefix = &prefix
+	}
+}
+
+// WithRunID make the Logger attach the process run ID (see RunID) to every
+// Printer as a field keyed RunIDKey.
+func WithRunID(attach bool) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.runID = attach
+	}
+}
+
+// WithLevelThreshold make the Logger attach the lowest Level it enabled, as
+// resolved from the LevelStore, to every Printer as a field keyed
+// LevelThresholdKey. It helps diagnosing level configurations.
+func WithLevelThreshold(attach bool) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.threshold = attach
+	}
+}
+
+// TimestampFormat decides how the timestamp field is rendered. See
+// WithTimestamp.
+type TimestampFormat int
+
+// Define TimestampFormats.
+const (
+	// TimestampNone adds no timestamp field.
+	TimestampNone TimestampFormat = iota
+	// TimestampRFC3339 renders the timestamp as an RFC 3339 string with
+	// nanoseconds.
+	TimestampRFC3339
+	// TimestampEpochMillis renders the timestamp as integer milliseconds
+	// since the Unix epoch.
+	TimestampEpochMillis
+)
+
+// format renders t in the format.
+func (f TimestampFormat) format(t time.Time) interface{} {
+	if f == TimestampEpochMillis {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// WithTimestamp make the Logger add a timestamp field keyed TimestampKey to
+// every Printer, in the provided format. The time is taken from the
+// registered Clock (see UseClock) when the Print methods are called, not when
+// the Printer is got. It gives OutPutters not stamping lines themselves the
+// time of records. Default is TimestampNone.
+func WithTimestamp(format TimestampFormat) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.timestamp = format
+	}
+}
+
+// WithCaller make the Logger add a field keyed CallerKey to every Printer,
+// with the `file:line` calling the Print methods. Unlike the log.Lshortfile
+// flag of the std OutPutter, the field is available to every OutPutter.
+func WithCaller(caller bool) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.caller = caller
+	}
+}
+
+// WithLevelStore bind the Logger to the provided LevelStore, i.e. one created
+// by NewLevelStore, instead of the one returned by GetLevelStore, which is
+// used by default or if nil is passed. Child Loggers got by Named are bound
+// to the same LevelStore.
+func WithLevelStore(store LevelStore) StdLoggerOption {
+	return func(l *stdLogger) {
+		l.levelStore = store
+	}
 }
 
 // NewStdLogger create a Logger by name. The Logger returned will use the provided
 // OutPutter to print logging messages.
-func NewStdLogger(name string, output OutPutter) Logger {
+func NewStdLogger(name string, output OutPutter, opts ...StdLoggerOption) Logger {
 	if output == nil {
 		output = NewStdOutPutter(log.Default())
 	}
-	return &stdLogger{
+	l := &stdLogger{
 		output: output,
 		name:   name,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+var _criticalAlwaysEmits = ua.NewBool(false)
+
+// SetCriticalAlwaysEmits make the builtin Loggers emit logs at ErrorLevel or
+// above even if the Logger is closed (its level is ClosedLevel).
+// It is a safety net: a misconfigured closed Logger should never fully
+// silence truly critical errors. Disabled by default.
+func SetCriticalAlwaysEmits(enable bool) {
+	_criticalAlwaysEmits.Store(enable)
 }
 
-func (l *stdLogger) levelEnabled(level Level) bool {
-	store := GetLevelStore()
-	ll := InfoLevel
-	if store != nil {
-		ll = store.Get(l.name)
+// levelThreshold returns the lowest Level the Logger enabled with ctx. A
+// Level forced by ctx (see WithForcedLevel) takes precedence over the
+// LevelStore.
+func (l *stdLogger) levelThreshold(ctx context.Context) Level {
+	if level, ok := forcedLevel(ctx); ok {
+		return level
+	}
+	store := l.levelStore
+	if store == nil {
+		store = GetLevelStore()
+	}
+	if store == nil {
+		return InfoLevel
 	}
-	return ll != ClosedLevel && ll <= level
+	return store.Get(l.name)
+}
+
+func (l *stdLogger) levelEnabled(ctx context.Context, level Level) bool {
+	if level == ClosedLevel {
+		return false
+	}
+	ll := l.levelThreshold(ctx)
+	if ll == ClosedLevel {
+		return _criticalAlwaysEmits.Load() && level >= ErrorLevel
+	}
+	return ll <= level
+}
+
+func (l *stdLogger) Enabled(ctx context.Context, level Level) bool {
+	return l.levelEnabled(ctx, level)
 }
 
 func (l *stdLogger) AtLevel(ctx context.Context, level Level) Printer {
-	if !l.levelEnabled(level) {
-		return NewNopPrinter()
+	p, _ := l.TryAtLevel(ctx, level)
+	return p
+}
+
+func (l *stdLogger) TryAtLevel(ctx context.Context, level Level) (Printer, bool) {
+	trackLoggerName(l.name)
+	enabled := l.levelEnabled(ctx, level)
+	if !enabled && level != FatalLevel {
+		return NewNopPrinter(), false
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return &stdPrinter{
+	p := &stdPrinter{
 		logger: l,
 		level:  level,
 		fields: []Field{
 			{LevelKey, level},
 			{LoggerKey, l.name},
 		},
-		ctx: ctx,
+		ctx:    ctx,
+		silent: !enabled,
 		bufPool: &sync.Pool{
 			New: func() interface{} {
 				return &bytes.Buffer{}
 			},
 		},
 	}
+	if l.timestamp != TimestampNone {
+		p.tsIndex = len(p.fields)
+		p.fields = append(p.fields, Field{TimestampKey, nil})
+	}
+	if l.caller {
+		p.callerIndex = len(p.fields)
+		p.fields = append(p.fields, Field{CallerKey, nil})
+	}
+	if l.threshold {
+		p.fields = append(p.fields, Field{LevelThresholdKey, l.levelThreshold(ctx)})
+	}
+	if l.runID {
+		p.fields = append(p.fields, Field{RunIDKey, RunID()})
+	}
+	for _, field := range contextFields() {
+		p.With(field.Key, field.Value)
+	}
+	for _, field := range MDCFields(ctx) {
+		p.With(field.Key, field.Value)
+	}
+	return p, enabled
+}
+
+func (l *stdLogger) AtLevels(ctx context.Context, levels ...Level) Printer {
+	m := &multiPrinter{}
+	for _, level := range levels {
+		if p, ok := l.AtLevel(ctx, level).(*stdPrinter); ok {
+			m.printers = append(m.printers, p)
+		}
+	}
+	switch len(m.printers) {
+	case 0:
+		return NewNopPrinter()
+	case 1:
+		return m.printers[0]
+	default:
+		return m
+	}
+}
+
+func (l *stdLogger) V(ctx context.Context, v int) Printer {
+	return l.AtLevel(ctx, VerbosityLevel(v))
+}
+
+func (l *stdLogger) Named(suffix string) Logger {
+	if len(suffix) == 0 {
+		return l
+	}
+	child := *l
+	if len(l.name) == 0 {
+		child.name = suffix
+	} else {
+		child.name = l.name + "." + suffix
+	}
+	return &child
 }
 
 // ======== LevelStore =========
@@ -280,12 +1558,28 @@ type LevelStore interface {
 	Restore(mp map[string]Level)
 	// Levels return all known levels as map[string]Level.
 	Levels() map[string]Level
+	// Watch register fn to be called with the old and new effective Level of
+	// the name, when a change of the LevelStore changes it, including changes
+	// on the ancestors of the name. The returned function cancels the watch.
+	Watch(name string, fn func(old, new Level)) (cancel func())
 }
 
-var _levelStore = &stdLevelStore{
-	store: ua.NewUnsafePointer(unsafe.Pointer(&map[string]Level{
-		"": InfoLevel,
-	})),
+var _levelStore = NewLevelStore(nil)
+
+// NewLevelStore create a LevelStore independent of the one returned by
+// GetLevelStore, with the levels in defaults set, i.e. for a subsystem
+// embedded in the process to have its own level namespace. The root level
+// (named by an empty string) is InfoLevel unless set by defaults.
+// A Logger uses the LevelStore if bound to it by WithLevelStore.
+func NewLevelStore(defaults map[string]Level) LevelStore {
+	levels := make(map[string]Level, len(defaults)+1)
+	levels[""] = InfoLevel
+	for name, level := range defaults {
+		levels[name] = level
+	}
+	return &stdLevelStore{
+		store: ua.NewUnsafePointer(unsafe.Pointer(&levelSnapshot{levels: levels})),
+	}
 }
 
 // GetLevelStore returns the registered LevelStore for use by default.
@@ -298,13 +1592,38 @@ func GetLevelStore() LevelStore {
 // stdLevelStore is builtin implementation of LevelStore.
 // It store and update levels with a Copy-On-Write map.
 type stdLevelStore struct {
-	store *ua.UnsafePointer
+	store     *ua.UnsafePointer
+	conflicts levelConflicts
+	watchers  levelWatchers
+}
+
+// levelSnapshot is an immutable snapshot of levels of a stdLevelStore. It
+// caches the effective levels resolved by name. Every change to the store
+// swaps in a new snapshot with an empty cache, so the cache is invalidated
+// together with the levels it was resolved from.
+type levelSnapshot struct {
+	levels map[string]Level
+	cache  sync.Map
 }
 
 var _ LevelStore = (*stdLevelStore)(nil)
 
+func (l *stdLevelStore) snapshot() *levelSnapshot {
+	return (*levelSnapshot)(l.store.Load())
+}
+
 func (l *stdLevelStore) Get(name string) Level {
-	store := *(*map[string]Level)(l.store.Load())
+	snapshot := l.snapshot()
+	if lvl, ok := snapshot.cache.Load(name); ok {
+		return lvl.(Level)
+	}
+	lvl := resolveLevel(snapshot.levels, name)
+	snapshot.cache.Store(name, lvl)
+	return lvl
+}
+
+// resolveLevel walks up the name hierarchy to find the effective level.
+func resolveLevel(store map[string]Level, name string) Level {
 	for name != "" {
 		if lvl, ok := store[name]; ok {
 			return lvl
@@ -321,14 +1640,22 @@ func (l *stdLevelStore) Get(name string) Level {
 }
 
 func (l *stdLevelStore) Set(name string, level Level) LevelStore {
+	return l.set(name, level, 1)
+}
+
+// set is Set with the call site checked for conflicts skip frames above the
+// caller of set, for the helpers setting levels on behalf of their callers.
+func (l *stdLevelStore) set(name string, level Level, skip int) LevelStore {
+	l.conflicts.check(name, level, skip+1)
 	for {
 		store := map[string]Level{}
-		old := (*map[string]Level)(l.store.Load())
-		for oldName, oldLevel := range *old {
+		old := l.snapshot()
+		for oldName, oldLevel := range old.levels {
 			store[oldName] = oldLevel
 		}
 		store[name] = level
-		if l.store.CAS(unsafe.Pointer(old), unsafe.Pointer(&store)) {
+		if l.store.CAS(unsafe.Pointer(old), unsafe.Pointer(&levelSnapshot{levels: store})) {
+			l.watchers.notify(old.levels, store)
 			break
 		}
 	}
@@ -338,14 +1665,15 @@ func (l *stdLevelStore) Set(name string, level Level) LevelStore {
 func (l *stdLevelStore) UnSet(name string) LevelStore {
 	for {
 		store := map[string]Level{}
-		old := (*map[string]Level)(l.store.Load())
-		for oldName, oldLevel := range *old {
+		old := l.snapshot()
+		for oldName, oldLevel := range old.levels {
 			if oldName == name {
 				continue
 			}
 			store[oldName] = oldLevel
 		}
-		if l.store.CAS(unsafe.Pointer(old), unsafe.Pointer(&store)) {
+		if l.store.CAS(unsafe.Pointer(old), unsafe.Pointer(&levelSnapshot{levels: store})) {
+			l.watchers.notify(old.levels, store)
 			break
 		}
 	}
@@ -357,11 +1685,12 @@ func (l *stdLevelStore) Restore(mp map[string]Level) {
 	for name, level := range mp {
 		store[name] = level
 	}
-	l.store.Swap(unsafe.Pointer(&store))
+	old := (*levelSnapshot)(l.store.Swap(unsafe.Pointer(&levelSnapshot{levels: store})))
+	l.watchers.notify(old.levels, store)
 }
 
 func (l *stdLevelStore) Levels() map[string]Level {
-	store := *(*map[string]Level)(l.store.Load())
+	store := l.snapshot().levels
 	mp := make(map[string]Level, len(store))
 	for name, level := range store {
 		mp[name] = level
@@ -369,13 +1698,19 @@ func (l *stdLevelStore) Levels() map[string]Level {
 	return mp
 }
 
+func (l *stdLevelStore) Watch(name string, fn func(old, new Level)) (cancel func()) {
+	return l.watchers.add(name, fn)
+}
+
 // ======== LoggerProvider =========
 
 // NewStdLoggerProvider make a LoggerProvider which produce Logger via
-// NewStdLogger function.
-func NewStdLoggerProvider(outPutter OutPutter) LoggerProvider {
+// NewStdLogger function. The options are applied to every Logger produced,
+// i.e. WithLevelStore makes the provider carry its own LevelStore; without
+// it, the Loggers fall back to the one returned by GetLevelStore.
+func NewStdLoggerProvider(outPutter OutPutter, opts ...StdLoggerOption) LoggerProvider {
 	return func(name string) Logger {
-		return NewStdLogger(name, outPutter)
+		return NewStdLogger(name, outPutter, opts...)
 	}
 }
 
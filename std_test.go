@@ -3,11 +3,18 @@ package log
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
+	"math/rand"
+	"regexp"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewStdOutPutter(t *testing.T) {
@@ -24,11 +31,414 @@ func TestNewStdOutPutter(t *testing.T) {
 		3)
 }
 
+func TestWithNumericLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	fields := []Field{
+		{LevelKey, WarnLevel},
+		{LoggerKey, ""},
+	}
+	o := NewStdOutPutter(log.New(w, "", 0), WithNumericLevel(true))
+	o.OutPut(context.Background(), "", WarnLevel, "abc", fields, 0)
+	expect := "level=1 logger= abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o = NewStdOutPutter(log.New(w, "", 0), WithLevelNumber(true))
+	o.OutPut(context.Background(), "", WarnLevel, "abc", fields, 0)
+	expect = "level=WARN level_num=1 logger= abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithLowercaseLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithLowercaseLevel(true))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{{LevelKey, InfoLevel}}, 0)
+	expect := "level=info abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if InfoLevel.String() != "INFO" {
+		t.Errorf("expect registered name INFO kept, got %s", InfoLevel.String())
+	}
+}
+
+func TestWithShortLevel(t *testing.T) {
+	defer resetLevelNames()
+	RegisterLevelName(Level(99), "fine")
+	tests := []struct {
+		level  Level
+		expect string
+	}{
+		{DebugLevel, "level=D abc\n"},
+		{InfoLevel, "level=I abc\n"},
+		{WarnLevel, "level=W abc\n"},
+		{ErrorLevel, "level=E abc\n"},
+		{Level(99), "level=F abc\n"},
+		{Level(100), "level=? abc\n"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("test%d", i), func(t *testing.T) {
+			w := &bytes.Buffer{}
+			o := NewStdOutPutter(log.New(w, "", 0), WithShortLevel(true))
+			o.OutPut(context.Background(), "", test.level, "abc", []Field{{LevelKey, test.level}}, 0)
+			if got := w.String(); got != test.expect {
+				t.Errorf("expect %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestWithDurationEncoder(t *testing.T) {
+	tests := []struct {
+		encoder DurationEncoder
+		d       time.Duration
+		expect  string
+	}{
+		{DurationString, time.Hour + 2*time.Minute + 3*time.Second, "d=1h2m3s abc\n"},
+		{DurationString, 1500 * time.Microsecond, "d=1.5ms abc\n"},
+		{DurationSeconds, 1500 * time.Millisecond, "d=1.5 abc\n"},
+		{DurationSeconds, 250 * time.Microsecond, "d=0.00025 abc\n"},
+		{DurationMillis, 1500 * time.Millisecond, "d=1500 abc\n"},
+		{DurationMillis, 250 * time.Microsecond, "d=0 abc\n"},
+		{DurationNanos, 1500 * time.Millisecond, "d=1500000000 abc\n"},
+		{DurationNanos, 250 * time.Microsecond, "d=250000 abc\n"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("test%d", i), func(t *testing.T) {
+			w := &bytes.Buffer{}
+			o := NewStdOutPutter(log.New(w, "", 0), WithDurationEncoder(test.encoder))
+			o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{{"d", test.d}}, 0)
+			if got := w.String(); got != test.expect {
+				t.Errorf("expect %q, got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestStdOutPutter_RawMessage(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0))
+	body := json.RawMessage(`{"user":"mike","tags":["a","b"]}`)
+	o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{{"body", body}}, 0)
+	expect := `body={"user":"mike","tags":["a","b"]} abc` + "\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithMaxMessageLen(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithMaxMessageLen(8))
+	o.OutPut(context.Background(), "", InfoLevel, "日志消息太长了", []Field{{"k", "v"}}, 0)
+	expect := "k=v msg_truncated=true 日志…\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o.OutPut(context.Background(), "", InfoLevel, "short", nil, 0)
+	expect = "short\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithMultilineSummary(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithMultilineSummary(true))
+	msg := "3 rows\r\n| a | b |\r\n| 1 | 2 |"
+	o.OutPut(context.Background(), "", InfoLevel, msg, []Field{{"k", "v"}}, 0)
+	expect := "k=v msg.full=" + msg + " 3 rows\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o.OutPut(context.Background(), "", InfoLevel, "single line", nil, 0)
+	expect = "single line\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithInterpolateTemplates(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithInterpolateTemplates(true))
+	fields := []Field{
+		{"user", Valuer(func(ctx context.Context) interface{} { return "mike" })},
+		{"n", 3},
+	}
+	o.OutPut(context.Background(), "", InfoLevel, "{user} logged in {n} times, {unknown} {user", fields, 0)
+	expect := "user=mike n=3 mike logged in 3 times, {unknown} {user\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o = NewStdOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "{user} logged in", fields, 0)
+	expect = "user=mike n=3 {user} logged in\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithCollapseDuplicateFields(t *testing.T) {
+	fields := []Field{
+		{"app", "demo"},
+		{"app", "demo"},
+		{"", "skipped"},
+		{"app", Valuer(func(ctx context.Context) interface{} { return "demo" })},
+		{"app", "other"},
+		{"ids", fieldValues{1, 2}},
+		{"ids", fieldValues{1, 2}},
+		{"app", "demo"},
+	}
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithCollapseDuplicateFields(true))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	expect := "app=demo app=other ids=1,2 ids=1,2 app=demo abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithValuerTimeout(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithValuerTimeout(10*time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+	fields := []Field{
+		{"slow", Valuer(func(ctx context.Context) interface{} {
+			<-release
+			return "late"
+		})},
+		{"fast", Valuer(func(ctx context.Context) interface{} { return "ok" })},
+		{"plain", 1},
+	}
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	expect := "slow=<timeout> fast=ok plain=1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithBufferInitialCap(t *testing.T) {
+	s := NewStdOutPutter(nil, WithBufferInitialCap(256)).(*stdOutPutter)
+	if got := s.bufPool.Get().(*bytes.Buffer).Cap(); got < 256 {
+		t.Errorf("expect buffer cap at least 256, got %d", got)
+	}
+	s = NewStdOutPutter(nil).(*stdOutPutter)
+	if got := s.bufPool.Get().(*bytes.Buffer).Cap(); got != 0 {
+		t.Errorf("expect empty buffer by default, got cap %d", got)
+	}
+}
+
+func TestWithTypedTextValues(t *testing.T) {
+	fields := []Field{
+		{"s", "abc"},
+		{"sb", "true"},
+		{"sn", "123"},
+		{"b", true},
+		{"n", 123},
+		{"f", 1.5},
+		{"u", uint8(7)},
+		{"nil", nil},
+		{"enum", testStatus(1)},
+		{"d", time.Second},
+		{"list", fieldValues{"a b", 1}},
+	}
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithTypedTextValues(true))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	expect := `s="abc" sb="true" sn="123" b=true n=123 f=1.5 u=7 nil=<nil> enum="DONE" d="1s" list="a b",1 abc` + "\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o = NewStdOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	expect = "s=abc sb=true sn=123 b=true n=123 f=1.5 u=7 nil=<nil> enum=DONE d=1s list=a b,1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithLevelField(t *testing.T) {
+	priority := func(level Level) interface{} {
+		if level >= ErrorLevel {
+			return "high"
+		}
+		return "low"
+	}
+	fields := []Field{{LevelKey, ErrorLevel}, {"other", WarnLevel}}
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0), WithLevelField(LevelField{Key: "priority", Encoder: priority}))
+	o.OutPut(context.Background(), "", ErrorLevel, "abc", fields, 0)
+	expect := "priority=high other=WARN abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o = NewStdOutPutter(log.New(w, "", 0), WithLevelField(LevelField{Key: "severity"}), WithLowercaseLevel(true))
+	o.OutPut(context.Background(), "", ErrorLevel, "abc", fields, 0)
+	expect = "severity=error other=warn abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdOutPutter_InsertionOrder(t *testing.T) {
+	keys := []string{"zeta", "alpha", "mu", "beta", "omega", "gamma", "kappa", "delta"}
+	expect := "level=INFO logger= zeta=0 alpha=1 mu=new beta=3 omega=4 gamma=5 kappa=6 delta=7 abc\n"
+	for i := 0; i < 100; i++ {
+		w := &bytes.Buffer{}
+		p := buildStdPrinter(context.Background(), w)
+		for j, key := range keys {
+			p.With(key, j)
+		}
+		// overriding a value keeps the position of the key
+		p.With("mu", "new").Print("abc")
+		if got := w.String(); got != expect {
+			t.Fatalf("expect %q, got %q", expect, got)
+		}
+	}
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	var global []error
+	SetInternalErrorHandler(func(err error) {
+		global = append(global, err)
+	})
+	defer SetInternalErrorHandler(nil)
+	var got []error
+	writeErr := errors.New("connection reset")
+	o := NewStdOutPutter(log.New(&failWriter{writeErr}, "", 0), WithErrorHandler(func(err error) {
+		got = append(got, err)
+	}))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+	if len(got) != 1 || got[0] != writeErr {
+		t.Errorf("expect handler fired with %v, got %v", writeErr, got)
+	}
+	if len(global) != 0 {
+		t.Errorf("expect internal error handler not fired, got %v", global)
+	}
+}
+
+func TestNewLogfmtOutPutter(t *testing.T) {
+	fields := []Field{
+		{LevelKey, InfoLevel},
+		{"simple", "token"},
+		{"space", "hello world"},
+		{"eq", "a=b"},
+		{"quote", `say "hi"`},
+		{"empty", ""},
+		{"newline", "a\nb"},
+		{"n", 42},
+		Flag("present"),
+	}
+	w := &bytes.Buffer{}
+	o := NewLogfmtOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "hello world", fields, 0)
+	o.OutPut(context.Background(), "", InfoLevel, "done", nil, 0)
+	expect := `level=INFO simple=token space="hello world" eq="a=b" quote="say \"hi\"" empty="" ` +
+		`newline="a\nb" n=42 present msg="hello world"` + "\n" +
+		"msg=done\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if got := Describe(o); got != "logfmt" {
+		t.Errorf("expect %q, got %q", "logfmt", got)
+	}
+	w.Reset()
+	o = NewStdOutPutter(log.New(w, "", 0))
+	o.OutPut(context.Background(), "", InfoLevel, "hello world", fields[1:3], 0)
+	expect = "simple=token space=hello world hello world\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithStableOutput(t *testing.T) {
+	fields := []Field{
+		{LevelKey, InfoLevel},
+		{LoggerKey, "pkg"},
+		{"user", "mike"},
+		{"app", "demo"},
+		{"trace", "t1"},
+	}
+	expect := "level=INFO logger=pkg app=demo trace=t1 user=mike abc\n"
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		shuffled := withFieldsCopy(fields)
+		r.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		w := &bytes.Buffer{}
+		o := NewStdOutPutter(log.New(w, "", 0), WithStableOutput(true))
+		o.OutPut(context.Background(), "pkg", InfoLevel, "abc", shuffled, 0)
+		if got := w.String(); got != expect {
+			t.Errorf("expect %q, got %q", expect, got)
+		}
+	}
+}
+
+func TestWithSortedFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	p := buildStdPrinter(context.Background(), w)
+	p.logger.output = NewLogfmtOutPutter(log.New(w, "", 0), WithSortedFields(true))
+	p.With("zeta", 1).With("alpha", 2).With("mu", 3).Print("abc")
+	expect := "level=INFO logger=\"\" alpha=2 mu=3 zeta=1 msg=abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
 func TestOutPutFilter_OutPut(t *testing.T) {
 	o := &OutPutFilter{}
 	o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
 }
 
+func TestNewOutPutFilter(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewOutPutFilter(NewStdOutPutter(log.New(w, "", 0)),
+		WithEnable(func(ctx context.Context, name string, level Level) bool {
+			return level >= InfoLevel
+		}),
+		WithEnable(func(ctx context.Context, name string, level Level) bool {
+			return name != "sensitive"
+		}),
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
+			if field.Key == "token" {
+				field.Key = ""
+			}
+		}),
+		WithFieldModifier(func(ctx context.Context, _ string, field *Field) {
+			if len(field.Key) == 0 {
+				t.Errorf("expect removed field not passed to the rest modifiers")
+			}
+			if field.Key == "passwd" {
+				field.Value = "***"
+			}
+		}),
+		WithFilterDescription("secure"),
+	)
+	fields := []Field{{"user", "mike"}, {"token", "t0"}, {"passwd", "dwssap"}}
+	o.OutPut(context.Background(), "sensitive", InfoLevel, "abc", fields, 0)
+	o.OutPut(context.Background(), "app", DebugLevel, "abc", fields, 0)
+	o.OutPut(context.Background(), "app", InfoLevel, "abc", fields, 0)
+	expect := "user=mike passwd=*** abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if got := Describe(o); got != "secure -> std" {
+		t.Errorf("expect %q, got %q", "secure -> std", got)
+	}
+	if o = NewOutPutFilter(nil); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
 func TestFilterEnable(t *testing.T) {
 	w := &bytes.Buffer{}
 	o := NewStdOutPutter(log.New(w, "", 0))
@@ -86,6 +496,68 @@ func TestFilterRemoveField(t *testing.T) {
 	}
 }
 
+func TestFilterRenameField(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := FilterRenameField(NewStdOutPutter(log.New(w, "", 0)), LoggerKey, "source")
+	fields := []Field{{LoggerKey, "db"}, {"source", "api"}}
+	o.OutPut(context.Background(), "db", InfoLevel, "abc", fields, 0)
+	o.OutPut(context.Background(), "db", InfoLevel, "abc", []Field{{"a", 1}}, 0)
+	expect := "source=db source=api abc\na=1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if fields[0].Key != LoggerKey {
+		t.Errorf("expect fields not modified, got %v", fields)
+	}
+	w.Reset()
+	o = FilterRenameField(NewLogfmtOutPutter(log.New(w, "", 0)), "msg", "message")
+	o.OutPut(context.Background(), "db", InfoLevel, "abc", []Field{{"a", 1}}, 0)
+	expect = "a=1 msg=abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect the message key kept, got %q", got)
+	}
+	if o = FilterRenameField(nil, "a", "b"); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
+func TestFilterRedactValue(t *testing.T) {
+	w := &bytes.Buffer{}
+	re := regexp.MustCompile(`[\w.]+@[\w.]+`)
+	o := FilterRedactValue(NewStdOutPutter(log.New(w, "", 0)), re, "<email>")
+	email := Valuer(func(ctx context.Context) interface{} { return "mike@example.com" })
+	o.OutPut(
+		context.Background(),
+		"",
+		InfoLevel,
+		"signed up",
+		[]Field{
+			{"contact", email},
+			{"status", testStatus(1)},
+			{"ids", []string{"a@b.c"}},
+			{"note", "cc jane@example.com, bob@example.com"},
+		},
+		0)
+	expect := "contact=<email> status=DONE ids=[a@b.c] note=cc <email>, <email> signed up\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger := NewStdLogger("", o)
+	logger.AtLevel(context.Background(), InfoLevel).
+		Append("cc", "jane@example.com").
+		Append("cc", email).
+		Append("cc", 1).
+		Print("sent")
+	expect = "level=INFO logger= cc=<email>,<email>,1 sent\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if o = FilterRedactValue(nil, re, ""); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
 func TestFilterCoverField(t *testing.T) {
 	w := &bytes.Buffer{}
 	o := NewStdOutPutter(log.New(w, "", 0))
@@ -111,6 +583,107 @@ func TestFilterCoverField(t *testing.T) {
 	}
 }
 
+func TestOutPutFilter_FieldsCopy(t *testing.T) {
+	w1, w2 := &bytes.Buffer{}, &bytes.Buffer{}
+	removing := FilterRemoveField(NewStdOutPutter(log.New(w1, "", 0)), "passwd")
+	covering := FilterCoverField(NewStdOutPutter(log.New(w2, "", 0)), "passwd", "***")
+	fields := []Field{
+		{"username", "mike"},
+		{"passwd", "dwssap"},
+	}
+	removing.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	covering.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	if expect, got := "username=mike abc\n", w1.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if expect, got := "username=mike passwd=*** abc\n", w2.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if fields[1].Key != "passwd" || fields[1].Value != "dwssap" {
+		t.Errorf("expect the fields of caller not modified, got %v", fields)
+	}
+}
+
+func TestFilterAddFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0))
+	n := 0
+	o = FilterAddFields(o, Field{"dc", "dc1"}, Field{"seq", Valuer(func(ctx context.Context) interface{} {
+		n++
+		return n
+	})})
+	for i := 1; i <= 2; i++ {
+		w.Reset()
+		o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{{LevelKey, InfoLevel}}, 0)
+		expect := fmt.Sprintf("dc=dc1 seq=%d level=INFO abc\n", i)
+		if got := w.String(); got != expect {
+			t.Errorf("expect %q, got %q", expect, got)
+		}
+	}
+	o = FilterAddFields(nil, Field{"dc", "dc1"})
+	if o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
+type prodKey struct{}
+
+func TestFilterWhen(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := NewStdOutPutter(log.New(w, "", 0))
+	o = FilterWhen(o, func(ctx context.Context) bool {
+		prod, _ := ctx.Value(prodKey{}).(bool)
+		return prod
+	}, FilterCoverField(o, "passwd", "***"))
+	prod := context.WithValue(context.Background(), prodKey{}, true)
+	o.OutPut(prod, "", InfoLevel, "abc", []Field{{"passwd", "dwssap"}}, 0)
+	expect := "passwd=*** abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o.OutPut(context.Background(), "", InfoLevel, "abc", []Field{{"passwd", "dwssap"}}, 0)
+	expect = "passwd=dwssap abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	o = FilterWhen(nil, func(ctx context.Context) bool { return false }, NewStdOutPutter(log.New(w, "", 0)))
+	o.OutPut(context.Background(), "", InfoLevel, "abc", nil, 0)
+	if w.Len() != 0 {
+		t.Errorf("expect nothing output, got %q", w.String())
+	}
+	if o = FilterWhen(nil, nil, nil); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
+func TestFilterMaskInProd(t *testing.T) {
+	w := &bytes.Buffer{}
+	prod := false
+	o := FilterMaskInProd(NewStdOutPutter(log.New(w, "", 0)), func(ctx context.Context) bool {
+		return prod
+	}, []string{"email", "phone"}, "***")
+	fields := []Field{{"email", "a@b.c"}, {"phone", "123"}, {"id", 1}}
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	prod = true
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	prod = false
+	o.OutPut(context.Background(), "", InfoLevel, "abc", fields, 0)
+	expect := "email=a@b.c phone=123 id=1 abc\n" +
+		"email=*** phone=*** id=1 abc\n" +
+		"email=a@b.c phone=123 id=1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if expect, got := "when(mask(email,phone) -> std | std)", Describe(o); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if o = FilterMaskInProd(nil, nil, nil, ""); o != nil {
+		t.Errorf("expect nil, but not")
+	}
+}
+
 func buildStdPrinter(ctx context.Context, w io.Writer) *stdPrinter {
 	return &stdPrinter{
 		logger: buildStdLogger("", w),
@@ -161,6 +734,23 @@ func TestStdPrinter_Println(t *testing.T) {
 	}
 }
 
+func TestWithPrintlnSeparator(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithPrintlnSeparator(", "))
+	logger.AtLevel(context.Background(), InfoLevel).Println(1, "abc", true)
+	expect := "level=INFO logger= 1, abc, true\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger = NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithPrintlnSeparator(""))
+	logger.AtLevel(context.Background(), InfoLevel).Println(1, "abc", true)
+	expect = "level=INFO logger= 1abctrue\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
 func TestStdPrinter_With(t *testing.T) {
 	w := &bytes.Buffer{}
 	printer := buildStdPrinter(context.Background(), w)
@@ -199,6 +789,194 @@ func TestStdPrinter_With(t *testing.T) {
 	}
 }
 
+func TestStdPrinter_Append(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.
+		Append("tag", "a").
+		Append("tag", "b").
+		Append("tag", "c").
+		Print("abc")
+	expect := "level=INFO logger= tag=a,b,c abc\n"
+	got := w.String()
+	if expect != got {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	printer = buildStdPrinter(context.Background(), w)
+	printer.
+		With("tag", "a").
+		Append("tag", "b").
+		Append("", "ignored").
+		Print("abc")
+	expect = "level=INFO logger= tag=a,b abc\n"
+	got = w.String()
+	if expect != got {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	buildStdLogger("app", w).AtLevel(context.Background(), InfoLevel).
+		Append("tag", "a").
+		Append("tag", NameValuer(func(name string) interface{} { return name })).
+		Print("abc")
+	expect = "level=INFO logger=app tag=a,app abc\n"
+	got = w.String()
+	if expect != got {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdPrinter_WithFields(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.With("a", 0).WithFields(Field{"a", 1}, Field{"", "skipped"}, Field{"b", 2}, Field{"b", 3}).Print("abc")
+	expect := "level=INFO logger= a=1 b=3 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdPrinter_WithError(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	err := fmt.Errorf("find user: %w", &queryError{"users", &codeError{1045}})
+	printer.WithError(nil).WithError(err).Print("abc")
+	expect := "level=INFO logger= error=find user: query users: code 1045 error.table=users error.code=1045 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if got := printer.fields[2].Value; got != err {
+		t.Errorf("expect the error value stored, got %v", got)
+	}
+}
+
+func TestStdPrinter_PanicLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdLogger("", w).AtLevel(context.Background(), PanicLevel)
+	defer func() {
+		if got := recover(); got != "abc 1" {
+			t.Errorf("expect panic with %q, got %v", "abc 1", got)
+		}
+		if expect := "level=PANIC logger= abc 1\n"; w.String() != expect {
+			t.Errorf("expect %q, got %q", expect, w.String())
+		}
+	}()
+	printer.Printf("abc %d", 1)
+	t.Errorf("expect panic, but not")
+}
+
+func TestStdPrinter_Event(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.Event("user.login").Print("mike logged in")
+	expect := "level=INFO logger= event=user.login mike logged in\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdPrinter_WithLabels(t *testing.T) {
+	labels := map[string]string{
+		"zone": "z1",
+		"app":  "demo",
+		"env":  "prod",
+	}
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.WithLabels(labels).Print("abc")
+	expect := "level=INFO logger= label.app=demo label.env=prod label.zone=z1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithLabelPrefix("k8s/"))
+	logger.AtLevel(context.Background(), InfoLevel).WithLabels(labels).Print("abc")
+	expect = "level=INFO logger= k8s/app=demo k8s/env=prod k8s/zone=z1 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdPrinter_WithFlag(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.WithFlag("present", true).Print("abc")
+	expect := "level=INFO logger= present abc\n"
+	got := w.String()
+	if expect != got {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	printer = buildStdPrinter(context.Background(), w)
+	printer.WithFlag("present", false).Print("abc")
+	expect = "level=INFO logger= abc\n"
+	got = w.String()
+	if expect != got {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+type testUser struct {
+	id   int
+	name string
+	team testTeam
+}
+
+func (u testUser) LogFields() []Field {
+	return []Field{{"id", u.id}, {"name", u.name}, {"team", u.team}}
+}
+
+type testTeam string
+
+func (t testTeam) LogFields() []Field {
+	return []Field{{"name", string(t)}}
+}
+
+func TestStdPrinter_WithValue(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.WithValue("user", testUser{1, "mike", "dev"}).WithValue("plain", 2).Print("abc")
+	expect := "level=INFO logger= user.id=1 user.name=mike user.team.name=dev plain=2 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+// testNode is a LogValuer referencing itself.
+type testNode struct {
+	next *testNode
+}
+
+func (n *testNode) LogFields() []Field {
+	return []Field{{"n", n.next}}
+}
+
+func (n *testNode) String() string {
+	return "node"
+}
+
+func TestStdPrinter_WithValue_Depth(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	node := &testNode{}
+	node.next = node
+	printer.WithValue("node", node).Print("abc")
+	expect := "level=INFO logger= node" + strings.Repeat(".n", maxLogValueDepth) + "=node abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdPrinter_WithValue_FieldError(t *testing.T) {
+	w := &bytes.Buffer{}
+	printer := buildStdPrinter(context.Background(), w)
+	printer.WithValue("err", &codeError{1045}).Print("abc")
+	expect := "level=INFO logger= err=code 1045 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
 func buildStdLogger(name string, w io.Writer) *stdLogger {
 	return &stdLogger{
 		output: NewStdOutPutter(log.New(w, "", 0)),
@@ -214,19 +992,38 @@ func TestStdLogger_LevelEnabled(t *testing.T) {
 		store.Set("", InfoLevel).UnSet("pkg")
 	}()
 	sub := buildStdLogger("pkg/sub", w)
-	if !sub.levelEnabled(DebugLevel) {
+	if !sub.levelEnabled(context.Background(), DebugLevel) {
 		t.Errorf("expect logger %s enabled DebugLevel as it parent, but not enabled",
 			"pkg/sub")
 	}
 	xyz := buildStdLogger("xyz", w)
-	if !xyz.levelEnabled(WarnLevel) {
+	if !xyz.levelEnabled(context.Background(), WarnLevel) {
 		t.Errorf("expect logger %s enabled WarnLevel as root, but not enabled", "xyz")
 	}
-	if xyz.levelEnabled(InfoLevel) {
+	if xyz.levelEnabled(context.Background(), InfoLevel) {
 		t.Errorf("expect logger %s not enabled InfoLevel as root, but enabled", "xyz")
 	}
 }
 
+func TestStdLogger_TraceLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	store := GetLevelStore()
+	store.Set("trace", TraceLevel)
+	defer store.UnSet("trace")
+	logger := buildStdLogger("trace", w)
+	logger.AtLevel(context.Background(), TraceLevel).Print("abc")
+	expect := "level=TRACE logger=trace abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	store.Set("trace", DebugLevel)
+	logger.AtLevel(context.Background(), TraceLevel).Print("abc")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+}
+
 func TestStdLogger_AtLevel(t *testing.T) {
 	w := &bytes.Buffer{}
 	GetLevelStore().Set("closed", ClosedLevel)
@@ -272,6 +1069,158 @@ func TestStdLogger_AtLevel(t *testing.T) {
 	}
 }
 
+func TestWithLevelThreshold(t *testing.T) {
+	w := &bytes.Buffer{}
+	GetLevelStore().Set("pkg", DebugLevel)
+	defer GetLevelStore().UnSet("pkg")
+	logger := NewStdLogger("pkg/sub", NewStdOutPutter(log.New(w, "", 0)), WithLevelThreshold(true))
+	logger.AtLevel(context.Background(), WarnLevel).Print("abc")
+	expect := "level=WARN logger=pkg/sub level_threshold=DEBUG abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestWithTimestamp(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	w := &bytes.Buffer{}
+	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithTimestamp(TimestampRFC3339))
+	p := logger.AtLevel(context.Background(), InfoLevel)
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	p.Print("abc")
+	expect := "level=INFO logger= ts=2021-01-01T00:00:01.5Z abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger = NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithTimestamp(TimestampEpochMillis))
+	logger.AtLevel(context.Background(), InfoLevel).Print("abc")
+	expect = "level=INFO logger= ts=1609459201500 abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger = NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)))
+	logger.AtLevel(context.Background(), InfoLevel).Print("abc")
+	expect = "level=INFO logger= abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdLogger_Named(t *testing.T) {
+	w := &bytes.Buffer{}
+	GetLevelStore().Set("app", DebugLevel)
+	defer GetLevelStore().UnSet("app")
+	root := NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)), WithRunID(true))
+	if root.Named("") != root {
+		t.Errorf("expect the same Logger for empty suffix")
+	}
+	child := root.Named("app").Named("db")
+	if name := child.(*stdLogger).name; name != "app.db" {
+		t.Errorf("expect name %q, got %q", "app.db", name)
+	}
+	child.AtLevel(context.Background(), DebugLevel).Print("abc")
+	expect := "level=DEBUG logger=app.db run_id=" + RunID() + " abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdLogger_Enabled(t *testing.T) {
+	GetLevelStore().Set("pkg", WarnLevel).Set("closed", ClosedLevel)
+	defer func() {
+		GetLevelStore().UnSet("pkg").UnSet("closed")
+	}()
+	logger := buildStdLogger("pkg", io.Discard)
+	ctx := context.Background()
+	if logger.Enabled(ctx, InfoLevel) || !logger.Enabled(ctx, WarnLevel) || !logger.Enabled(ctx, ErrorLevel) {
+		t.Errorf("expect enabled at and above WarnLevel")
+	}
+	if logger.Enabled(ctx, ClosedLevel) {
+		t.Errorf("expect ClosedLevel never enabled")
+	}
+	if buildStdLogger("closed", io.Discard).Enabled(ctx, ErrorLevel) {
+		t.Errorf("expect closed Logger disabled")
+	}
+}
+
+func TestStdLogger_TryAtLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	GetLevelStore().Set("pkg", WarnLevel)
+	defer GetLevelStore().UnSet("pkg")
+	logger := buildStdLogger("pkg", w)
+	if p, ok := logger.TryAtLevel(context.Background(), InfoLevel); ok {
+		t.Errorf("expect InfoLevel disabled")
+	} else if _, nop := p.(*nopPrinter); !nop {
+		t.Errorf("expect nop Printer, got %T", p)
+	}
+	p, ok := logger.TryAtLevel(context.Background(), WarnLevel)
+	if !ok {
+		t.Fatalf("expect WarnLevel enabled")
+	}
+	p.Print("abc")
+	expect := "level=WARN logger=pkg abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdLogger_AtLevels(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := buildStdLogger("", w)
+	logger.AtLevels(context.Background(), InfoLevel, WarnLevel).With("k", "v").Println("a", "b")
+	expect := "level=INFO logger= k=v a b\nlevel=WARN logger= k=v a b\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger.AtLevels(context.Background(), DebugLevel, WarnLevel).Printf("%s", "abc")
+	expect = "level=WARN logger= abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w.Reset()
+	logger.AtLevels(context.Background(), DebugLevel).Print("abc")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+	w.Reset()
+	logger.AtLevels(context.Background(), InfoLevel, ErrorLevel).
+		WithFlag("f", true).
+		Append("tag", "a").
+		Count("c", 1).
+		Event("e").
+		WithLabels(map[string]string{"app": "demo"}).
+		Print("abc")
+	expect = "level=INFO logger= f tag=a c=1 event=e label.app=demo abc\n" +
+		"level=ERROR logger= f tag=a c=1 event=e label.app=demo abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestSetCriticalAlwaysEmits(t *testing.T) {
+	w := &bytes.Buffer{}
+	GetLevelStore().Set("closed", ClosedLevel)
+	defer GetLevelStore().UnSet("closed")
+	defer SetCriticalAlwaysEmits(false)
+	closed := buildStdLogger("closed", w)
+	closed.AtLevel(context.Background(), ErrorLevel).Print("critical")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+	SetCriticalAlwaysEmits(true)
+	closed.AtLevel(context.Background(), WarnLevel).Print("warning")
+	closed.AtLevel(context.Background(), ErrorLevel).Print("critical")
+	expect := "level=ERROR logger=closed critical\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
 func TestCaller(t *testing.T) {
 	w := &bytes.Buffer{}
 	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", log.Lshortfile)))
@@ -286,3 +1235,170 @@ func TestCaller(t *testing.T) {
 		t.Errorf("expect sl.output not nil, but is nil")
 	}
 }
+
+func TestCaller_Filters(t *testing.T) {
+	w := &bytes.Buffer{}
+	std := NewStdOutPutter(log.New(w, "", log.Lshortfile))
+	outPutters := []OutPutter{
+		std,
+		FilterRemoveField(std, "a"),
+		FilterCoverField(FilterRemoveField(std, "a"), "b", "***"),
+		FilterWhen(std, func(ctx context.Context) bool { return true }, FilterRemoveField(std, "a")),
+		NewTeeOutPutter(FilterRemoveField(std, "a")),
+		NewContextRoutingOutPutter(func(ctx context.Context) OutPutter { return nil }, FilterRemoveField(std, "a")),
+		FilterGlobalRateLimit(FilterSample(std, time.Minute, 100, 0), 100, 100),
+	}
+	for i, o := range outPutters {
+		w.Reset()
+		logger := NewStdLogger("", o)
+		_, _, line, _ := runtime.Caller(0)
+		logger.AtLevel(context.Background(), InfoLevel).Print("abc")
+		expect := fmt.Sprintf("std_test.go:%d: ", line+1)
+		if got := w.String(); !strings.HasPrefix(got, expect) {
+			t.Errorf("%d: expect prefix %q, got %q", i, expect, got)
+		}
+	}
+}
+
+func TestWithCaller(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", log.Lshortfile)), WithCaller(true))
+	logger.AtLevel(context.Background(), InfoLevel).Printf("abc")
+	logger.AtLevels(context.Background(), InfoLevel, WarnLevel).Println("abc")
+	lines := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expect 3 lines, got %q", w.String())
+	}
+	for _, line := range lines {
+		// log.Lshortfile reports the same file:line as the caller field
+		i := strings.Index(line, ": ")
+		if i < 0 || !strings.Contains(line, " caller="+line[:i]+" ") {
+			t.Errorf("expect caller field equal to %q, got %q", line[:i], line)
+		}
+	}
+}
+
+func TestNewLevelStore(t *testing.T) {
+	defaults := map[string]Level{"db": DebugLevel}
+	store := NewLevelStore(defaults)
+	defaults["db"] = ErrorLevel
+	if got := store.Get("db.pool"); got != DebugLevel {
+		t.Errorf("expect %v, got %v", DebugLevel, got)
+	}
+	if got := store.Get("xyz"); got != InfoLevel {
+		t.Errorf("expect %v, got %v", InfoLevel, got)
+	}
+	if got := NewLevelStore(map[string]Level{"": WarnLevel}).Get("xyz"); got != WarnLevel {
+		t.Errorf("expect %v, got %v", WarnLevel, got)
+	}
+
+	w := &bytes.Buffer{}
+	logger := NewStdLogger("db", NewStdOutPutter(log.New(w, "", 0)), WithLevelStore(store))
+	logger.Named("pool").AtLevel(context.Background(), DebugLevel).Print("abc")
+	expect := "level=DEBUG logger=db.pool abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if GetLevelStore().Get("db") != InfoLevel {
+		t.Errorf("expect the global LevelStore not affected")
+	}
+	store.Set("db", ClosedLevel)
+	if logger.Enabled(context.Background(), ErrorLevel) {
+		t.Errorf("expect ErrorLevel not enabled after closing db, but enabled")
+	}
+}
+
+func TestNewStdLoggerProvider_LevelStore(t *testing.T) {
+	w1, w2 := &bytes.Buffer{}, &bytes.Buffer{}
+	store1 := NewLevelStore(map[string]Level{"db": DebugLevel})
+	store2 := NewLevelStore(map[string]Level{"db": ErrorLevel})
+	p1 := NewStdLoggerProvider(NewStdOutPutter(log.New(w1, "", 0)), WithLevelStore(store1))
+	p2 := NewStdLoggerProvider(NewStdOutPutter(log.New(w2, "", 0)), WithLevelStore(store2))
+	for _, level := range []Level{DebugLevel, WarnLevel, ErrorLevel} {
+		p1("db").AtLevel(context.Background(), level).Print("abc")
+		p2("db").AtLevel(context.Background(), level).Print("abc")
+	}
+	expect := "level=DEBUG logger=db abc\nlevel=WARN logger=db abc\nlevel=ERROR logger=db abc\n"
+	if got := w1.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if expect, got := "level=ERROR logger=db abc\n", w2.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	w1.Reset()
+	store1.Set("db", ClosedLevel)
+	p1("db").AtLevel(context.Background(), ErrorLevel).Print("abc")
+	p2("db").AtLevel(context.Background(), ErrorLevel).Print("abc")
+	if w1.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w1.String())
+	}
+	if expect, got := "level=ERROR logger=db abc\nlevel=ERROR logger=db abc\n", w2.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestStdLevelStore_Get(t *testing.T) {
+	store := GetLevelStore()
+	defer store.UnSet("cache")
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(lvl Level) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				store.Set("cache", lvl)
+			}
+		}(Level(i))
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = store.Get("cache.sub")
+			}
+		}()
+	}
+	wg.Wait()
+	store.Set("cache", ErrorLevel)
+	if got := store.Get("cache.sub"); got != ErrorLevel {
+		t.Errorf("expect %v, got %v", ErrorLevel, got)
+	}
+	store.UnSet("cache")
+	if got := store.Get("cache.sub"); got != store.Get("") {
+		t.Errorf("expect %v, got %v", store.Get(""), got)
+	}
+}
+
+func BenchmarkStdLevelStore_Get(b *testing.B) {
+	store := GetLevelStore()
+	store.Set("bench", DebugLevel)
+	defer store.UnSet("bench")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = store.Get("bench/a/b/c.d")
+	}
+}
+
+func BenchmarkResolveLevel(b *testing.B) {
+	store := GetLevelStore()
+	store.Set("bench", DebugLevel)
+	defer store.UnSet("bench")
+	levels := store.Levels()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = resolveLevel(levels, "bench/a/b/c.d")
+	}
+}
+
+func BenchmarkWithBufferInitialCap(b *testing.B) {
+	line := strings.Repeat("x", 200)
+	for _, n := range []int{0, 256} {
+		b.Run(fmt.Sprintf("cap%d", n), func(b *testing.B) {
+			s := NewStdOutPutter(log.New(io.Discard, "", 0), WithBufferInitialCap(n)).(*stdOutPutter)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// a new buffer per line, as when the pool is drained by GC
+				buf := s.bufPool.New().(*bytes.Buffer)
+				buf.WriteString(line)
+			}
+		})
+	}
+}
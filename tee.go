@@ -0,0 +1,40 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// teeOutPutter outputs each record to all of its members.
+type teeOutPutter []OutPutter
+
+// NewTeeOutPutter create a OutPutter which outputs each record to every one
+// of outs in order, with the same arguments, i.e. to write both text to the
+// standard output and JSON to a file. Nil members are skipped.
+// The fields slice is shared by the members, none of them may alter it (see
+// OutPutter).
+func NewTeeOutPutter(outs ...OutPutter) OutPutter {
+	t := make(teeOutPutter, 0, len(outs))
+	for _, o := range outs {
+		if o != nil {
+			t = append(t, o)
+		}
+	}
+	return t
+}
+
+func (t teeOutPutter) OutPut(
+	ctx context.Context, name string, level Level, msg string, fields []Field, callDepth int) {
+	for _, o := range t {
+		o.OutPut(ctx, name, level, msg, fields, callDepth+1)
+	}
+}
+
+func (t teeOutPutter) Describe() string {
+	descs := make([]string, 0, len(t))
+	for _, o := range t {
+		descs = append(descs, Describe(o))
+	}
+	return fmt.Sprintf("tee(%s)", strings.Join(descs, ", "))
+}
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+)
+
+func TestNewTeeOutPutter(t *testing.T) {
+	w1, w2 := &bytes.Buffer{}, &bytes.Buffer{}
+	o := NewTeeOutPutter(
+		NewStdOutPutter(log.New(w1, "", 0)),
+		nil,
+		NewStdOutPutter(log.New(w2, "", log.Lshortfile), WithLowercaseLevel(true)),
+	)
+	logger := NewStdLogger("pkg", o)
+	logger.AtLevel(context.Background(), WarnLevel).Print("abc")
+	if expect := "level=WARN logger=pkg abc\n"; w1.String() != expect {
+		t.Errorf("expect %q, got %q", expect, w1.String())
+	}
+	if expect := "tee_test.go:18: level=warn logger=pkg abc\n"; w2.String() != expect {
+		t.Errorf("expect %q, got %q", expect, w2.String())
+	}
+	if expect := "tee(std, std)"; Describe(o) != expect {
+		t.Errorf("expect %q, got %q", expect, Describe(o))
+	}
+}
@@ -0,0 +1,23 @@
+package log
+
+import "context"
+
+// Timer starts timing, and returns a function which logs msg with the time
+// elapsed since Timer was called as a field keyed DurationKey, via the Logger
+// at the level. It is usually deferred:
+//
+//	defer log.Timer(logger, ctx, log.InfoLevel, "handled request")()
+//
+// Time is measured by the registered Clock (see UseClock).
+func Timer(logger Logger, ctx context.Context, level Level, msg string) func() {
+	start := clockNow()
+	return func() {
+		p := logger.AtLevel(ctx, level).With(DurationKey, clockNow().Sub(start))
+		if dp, ok := p.(depthPrinter); ok {
+			// report the caller of the returned function
+			dp.printDepth(1, msg)
+			return
+		}
+		p.Print(msg)
+	}
+}
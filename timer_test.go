@@ -0,0 +1,51 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	w := &bytes.Buffer{}
+	done := Timer(buildStdLogger("pkg", w), context.Background(), InfoLevel, "handled request")
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	done()
+	expect := "level=INFO logger=pkg duration=1.5s handled request\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestTimer_Caller(t *testing.T) {
+	w := &bytes.Buffer{}
+	logger := NewStdLogger("", NewStdOutPutter(log.New(w, "", log.Lshortfile)), WithCaller(true))
+	loggers := []Logger{logger, multiLevelLogger{logger}}
+	for i, l := range loggers {
+		w.Reset()
+		done := Timer(l, context.Background(), InfoLevel, "abc")
+		_, _, line, _ := runtime.Caller(0)
+		done()
+		expect := fmt.Sprintf("timer_test.go:%d", line+1)
+		if got := w.String(); !strings.HasPrefix(got, expect+": ") || !strings.Contains(got, " caller="+expect+" ") {
+			t.Errorf("%d: expect caller %q, got %q", i, expect, got)
+		}
+	}
+}
+
+// multiLevelLogger is a Logger getting Printers at the level and WarnLevel.
+type multiLevelLogger struct {
+	Logger
+}
+
+func (l multiLevelLogger) AtLevel(ctx context.Context, level Level) Printer {
+	return l.AtLevels(ctx, level, WarnLevel)
+}
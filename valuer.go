@@ -1,19 +1,70 @@
 package log
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Valuer is function that calculating real value at call time.
 // Note that the ctx may be nil.
 type Valuer func(ctx context.Context) interface{}
 
-// Value calculate and return the value if v is a Valuer, or just return v.
+// SimpleValuer is function that calculating real value at call time, for
+// values not depending on the context. A plain `func() interface{}` value is
+// treated the same.
+type SimpleValuer func() interface{}
+
+// Value calculate and return the value if v is a Valuer or a SimpleValuer,
+// or just return v.
 func Value(ctx context.Context, v interface{}) interface{} {
 	for {
-		if valuer, ok := v.(Valuer); ok {
+		switch valuer := v.(type) {
+		case Valuer:
 			v = valuer(ctx)
-			continue
+		case SimpleValuer:
+			v = valuer()
+		case func() interface{}:
+			v = valuer()
+		default:
+			return v
+		}
+	}
+}
+
+// NameValuer is function that calculating real value at output time from the
+// name of the Logger the record is output by, i.e. to derive a component tag
+// from the Logger name. It is resolved by OutPutters which know the name, see
+// NamedValue.
+type NameValuer func(name string) interface{}
+
+// NamedValue calculate and return the value like Value, but NameValuers are
+// also resolved, with the name passed to them.
+func NamedValue(ctx context.Context, name string, v interface{}) interface{} {
+	for {
+		switch valuer := v.(type) {
+		case NameValuer:
+			v = valuer(name)
+		case Valuer, SimpleValuer, func() interface{}:
+			v = Value(ctx, valuer)
+		default:
+			return v
 		}
-		break
 	}
-	return v
+}
+
+// TimeValuer returns a Valuer resolving to the current time formatted with
+// the layout, i.e. time.RFC3339. The time is taken from the registered Clock
+// (see UseClock) on each resolving.
+func TimeValuer(layout string) Valuer {
+	return func(_ context.Context) interface{} {
+		return clockNow().Format(layout)
+	}
+}
+
+// SinceValuer returns a Valuer resolving to the time.Duration elapsed since
+// start, measured by the registered Clock (see UseClock) on each resolving.
+func SinceValuer(start time.Time) Valuer {
+	return func(_ context.Context) interface{} {
+		return clockNow().Sub(start)
+	}
 }
@@ -1,10 +1,13 @@
 package log
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type testKey struct{}
@@ -32,3 +35,95 @@ func TestValue(t *testing.T) {
 		})
 	}
 }
+
+func TestNamedValue(t *testing.T) {
+	component := NameValuer(func(name string) interface{} {
+		return "component:" + name
+	})
+	nested := Valuer(func(ctx context.Context) interface{} {
+		return component
+	})
+	tests := []struct {
+		v      interface{}
+		expect interface{}
+	}{
+		{"abc", "abc"},
+		{nil, nil},
+		{component, "component:pkg"},
+		{nested, "component:pkg"},
+	}
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("test%d", i), func(t *testing.T) {
+			value := NamedValue(context.Background(), "pkg", test.v)
+			if !reflect.DeepEqual(value, test.expect) {
+				t.Errorf("expect %q, got %q", test.expect, value)
+			}
+		})
+	}
+}
+
+func TestNameValuer_OutPut(t *testing.T) {
+	w := &bytes.Buffer{}
+	l := NewStdLogger("pkg/sub", NewStdOutPutter(log.New(w, "", 0)))
+	l.AtLevel(context.Background(), InfoLevel).
+		With("name", NameValuer(func(name string) interface{} { return name })).
+		Print("abc")
+	expect := "level=INFO logger=pkg/sub name=pkg/sub abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestValue_SimpleValuer(t *testing.T) {
+	calls := 0
+	lazy := func() interface{} {
+		calls++
+		return "lazy"
+	}
+	w := &bytes.Buffer{}
+	l := NewStdLogger("", NewStdOutPutter(log.New(w, "", 0)))
+	p := l.AtLevel(context.Background(), InfoLevel).With("plain", lazy).With("simple", SimpleValuer(lazy))
+	if calls != 0 {
+		t.Errorf("expect lazy evaluation, called %d times", calls)
+	}
+	p.Print("abc")
+	expect := "level=INFO logger= plain=lazy simple=lazy abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	nested := Valuer(func(ctx context.Context) interface{} { return SimpleValuer(lazy) })
+	if got := Value(context.Background(), nested); got != "lazy" {
+		t.Errorf("expect %q, got %v", "lazy", got)
+	}
+	if got := NamedValue(context.Background(), "", nested); got != "lazy" {
+		t.Errorf("expect %q, got %v", "lazy", got)
+	}
+}
+
+func TestTimeValuer(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	v := TimeValuer(time.RFC3339)
+	if got, expect := Value(context.Background(), v), "2021-01-01T00:00:00Z"; got != expect {
+		t.Errorf("expect %q, got %v", expect, got)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if got, expect := Value(context.Background(), v), "2021-01-01T00:01:00Z"; got != expect {
+		t.Errorf("expect %q, got %v", expect, got)
+	}
+}
+
+func TestSinceValuer(t *testing.T) {
+	clock := newFakeClock()
+	UseClock(clock)
+	defer UseClock(nil)
+	v := SinceValuer(clock.now)
+	if got := Value(context.Background(), v); got != time.Duration(0) {
+		t.Errorf("expect 0s, got %v", got)
+	}
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	if got := Value(context.Background(), v); got != 1500*time.Millisecond {
+		t.Errorf("expect 1.5s, got %v", got)
+	}
+}
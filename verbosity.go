@@ -0,0 +1,29 @@
+package log
+
+import "math"
+
+// VerbosityLevel returns the Level of the numeric verbosity v, used by
+// Logger.V. Verbosity 0 is InfoLevel, and each greater verbosity is one Level
+// lower, starting strictly below the predefined Levels, i.e. verbosity 1 is
+// one Level below TraceLevel. So the verbosity of V(0) is enabled by default,
+// and DebugLevel or TraceLevel never enables a verbosity above 0. As the
+// verbosity is stored as the Level threshold, setting a verbosity above 0
+// enables DebugLevel and TraceLevel too. Negative v is taken as 0, and v is
+// capped at the lowest Level.
+func VerbosityLevel(v int) Level {
+	if v <= 0 {
+		return InfoLevel
+	}
+	if max := int(TraceLevel) - math.MinInt8; v > max {
+		v = max
+	}
+	return TraceLevel - Level(v)
+}
+
+// SetVerbosity set the numeric verbosity of the Logger by name in the
+// LevelStore, so that Logger.V with verbosity up to v is enabled, and all the
+// predefined Levels too if v is above 0. It is the same as setting the Level
+// by VerbosityLevel(v), i.e. verbosity 0 restores InfoLevel.
+func SetVerbosity(name string, v int) {
+	setLevel(name, VerbosityLevel(v), 1)
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+)
+
+func TestVerbosityLevel(t *testing.T) {
+	tests := map[int]Level{
+		-1:   InfoLevel,
+		0:    InfoLevel,
+		1:    Level(-3),
+		3:    Level(-5),
+		1000: Level(math.MinInt8),
+	}
+	for v, expect := range tests {
+		if got := VerbosityLevel(v); got != expect {
+			t.Errorf("%d: expect %v, got %v", v, expect, got)
+		}
+	}
+}
+
+func TestSetVerbosity(t *testing.T) {
+	w := &bytes.Buffer{}
+	SetVerbosity("verbose", 2)
+	defer GetLevelStore().UnSet("verbose")
+	logger := buildStdLogger("verbose", w)
+	logger.V(context.Background(), 3).Print("abc")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing, got %q", w.String())
+	}
+	logger.V(context.Background(), 2).Print("abc")
+	expect := "level=Level(-4) logger=verbose abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestSetVerbosity_Zero(t *testing.T) {
+	w := &bytes.Buffer{}
+	SetVerbosity("verbose", 2)
+	SetVerbosity("verbose", 0)
+	defer GetLevelStore().UnSet("verbose")
+	if got := GetLevelStore().Get("verbose"); got != InfoLevel {
+		t.Errorf("expect %v, got %v", InfoLevel, got)
+	}
+	logger := buildStdLogger("verbose", w)
+	logger.AtLevel(context.Background(), DebugLevel).Print("abc")
+	if w.Len() != 0 {
+		t.Errorf("should not enable DebugLevel, got %q", w.String())
+	}
+	logger.V(context.Background(), 0).Print("abc")
+	expect := "level=INFO logger=verbose abc\n"
+	if got := w.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestVerbosity_DebugLevel(t *testing.T) {
+	w := &bytes.Buffer{}
+	SetLevel("verbose", TraceLevel)
+	defer GetLevelStore().UnSet("verbose")
+	logger := buildStdLogger("verbose", w)
+	logger.V(context.Background(), 1).Print("abc")
+	if w.Len() != 0 {
+		t.Errorf("should print nothing at TraceLevel, got %q", w.String())
+	}
+}
+
+func TestSetVerbosity_EnablesDebugLevel(t *testing.T) {
+	SetVerbosity("verbose", 1)
+	defer GetLevelStore().UnSet("verbose")
+	logger := buildStdLogger("verbose", &bytes.Buffer{})
+	for _, level := range []Level{TraceLevel, DebugLevel} {
+		if !logger.Enabled(context.Background(), level) {
+			t.Errorf("expect %v enabled by verbosity 1", level)
+		}
+	}
+}
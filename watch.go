@@ -0,0 +1,54 @@
+package log
+
+import "sync"
+
+// levelWatcher is a watch registered to a LevelStore.
+type levelWatcher struct {
+	name string
+	fn   func(old, new Level)
+}
+
+// levelWatchers notifies the watches of a stdLevelStore.
+type levelWatchers struct {
+	mu       sync.Mutex
+	nextID   int
+	watchers map[int]levelWatcher
+}
+
+// add registers a watch, and returns the function to cancel it.
+func (w *levelWatchers) add(name string, fn func(old, new Level)) (cancel func()) {
+	if fn == nil {
+		return func() {}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watchers == nil {
+		w.watchers = map[int]levelWatcher{}
+	}
+	id := w.nextID
+	w.nextID++
+	w.watchers[id] = levelWatcher{name: name, fn: fn}
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.watchers, id)
+	}
+}
+
+// notify calls the watches whose effective levels differ between the old and
+// new levels. The watches are called without holding the lock, so they may
+// change the LevelStore or the watches.
+func (w *levelWatchers) notify(old, new map[string]Level) {
+	w.mu.Lock()
+	watchers := make([]levelWatcher, 0, len(w.watchers))
+	for _, watcher := range w.watchers {
+		watchers = append(watchers, watcher)
+	}
+	w.mu.Unlock()
+	for _, watcher := range watchers {
+		oldLevel, newLevel := resolveLevel(old, watcher.name), resolveLevel(new, watcher.name)
+		if oldLevel != newLevel {
+			watcher.fn(oldLevel, newLevel)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStdLevelStore_Watch(t *testing.T) {
+	store := newTestLevelStore()
+	var changes [][2]Level
+	cancel := store.Watch("app.db", func(old, new Level) {
+		changes = append(changes, [2]Level{old, new})
+		// watches may use the store without deadlock
+		_ = store.Get("app.db")
+	})
+	store.Set("app", DebugLevel)
+	store.Set("other", ErrorLevel)
+	store.Set("app.db", DebugLevel)
+	store.Set("app.db", WarnLevel)
+	store.UnSet("app.db")
+	store.Restore(map[string]Level{"": ErrorLevel})
+	cancel()
+	store.Set("app.db", InfoLevel)
+	expect := [][2]Level{
+		{InfoLevel, DebugLevel},
+		{DebugLevel, WarnLevel},
+		{WarnLevel, DebugLevel},
+		{DebugLevel, ErrorLevel},
+	}
+	if !reflect.DeepEqual(changes, expect) {
+		t.Errorf("expect %v, got %v", expect, changes)
+	}
+}
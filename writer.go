@@ -0,0 +1,108 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// PartialLineKey is field key for marking the message is a part of a line
+// too long to buffer. See LevelWriter.
+const PartialLineKey = "partial_line"
+
+// maxLevelWriterLine is the buffer limit of the Writer returned by
+// LevelWriter.
+const maxLevelWriterLine = 64 << 10
+
+// levelWriter is a line buffered io.WriteCloser logging each line.
+type levelWriter struct {
+	mu     sync.Mutex
+	logger Logger
+	ctx    context.Context
+	level  Level
+	buf    []byte
+	max    int
+}
+
+// LevelWriter create an io.WriteCloser which logs each line written to it as
+// a message at the provided level, i.e. to capture the output of a
+// subprocess via exec.Cmd.Stdout or exec.Cmd.Stderr. Line endings are
+// trimmed, and an unterminated last line is logged on Close.
+// Lines longer than 64KiB are not buffered unboundedly: they are logged in
+// parts, each part but the last is flagged with PartialLineKey.
+// The returned Writer is safe for concurrent use.
+func LevelWriter(logger Logger, level Level) io.WriteCloser {
+	return &levelWriter{
+		logger: logger,
+		ctx:    context.Background(),
+		level:  level,
+		max:    maxLevelWriterLine,
+	}
+}
+
+// WriterAt create an io.Writer which logs each line written to it as a
+// message at the provided level with ctx, i.e. to redirect third-party
+// libraries accepting only an io.Writer. It works like LevelWriter, and a
+// trailing partial line is buffered until the next newline. The returned
+// Writer implements io.Closer too, which logs the buffered partial line.
+// If ctx is nil, context.Background() will be used.
+func WriterAt(logger Logger, ctx context.Context, level Level) io.Writer {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &levelWriter{
+		logger: logger,
+		ctx:    ctx,
+		level:  level,
+		max:    maxLevelWriterLine,
+	}
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		chunk := p
+		if i >= 0 {
+			chunk = p[:i]
+		}
+		w.buf = append(w.buf, chunk...)
+		for len(w.buf) > w.max {
+			rest := w.buf[w.max:]
+			w.buf = w.buf[:w.max]
+			w.flush(true)
+			w.buf = append(w.buf, rest...)
+		}
+		if i < 0 {
+			break
+		}
+		p = p[i+1:]
+		w.flush(false)
+	}
+	return n, nil
+}
+
+// Close logs the buffered unterminated line, if any.
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.flush(false)
+	}
+	return nil
+}
+
+// flush logs the buffered line and empties the buffer.
+func (w *levelWriter) flush(partial bool) {
+	line := w.buf
+	if !partial {
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+	}
+	w.logger.AtLevel(w.ctx, w.level).
+		WithFlag(PartialLineKey, partial).
+		Print(string(line))
+	w.buf = w.buf[:0]
+}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLevelWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := LevelWriter(buildStdLogger("cmd", out), WarnLevel)
+	chunks := []string{
+		"step 1 done\nstep",
+		" 2 done\r\n",
+		"\nwarning: ",
+		"disk almost full\nexit",
+	}
+	for _, chunk := range chunks {
+		if n, err := w.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("unexpected write result: %d, %v", n, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expect := "level=WARN logger=cmd step 1 done\n" +
+		"level=WARN logger=cmd step 2 done\n" +
+		"level=WARN logger=cmd \n" +
+		"level=WARN logger=cmd warning: disk almost full\n" +
+		"level=WARN logger=cmd exit\n"
+	if got := out.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
+
+func TestLevelWriter_LongLine(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := LevelWriter(buildStdLogger("cmd", out), InfoLevel)
+	w.(*levelWriter).max = 4
+	_, _ = w.Write([]byte("abcdefghij\nxyzw\n"))
+	expect := "level=INFO logger=cmd partial_line abcd\n" +
+		"level=INFO logger=cmd partial_line efgh\n" +
+		"level=INFO logger=cmd ij\n" +
+		"level=INFO logger=cmd xyzw\n"
+	if got := out.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	if l := len(w.(*levelWriter).buf); l != 0 {
+		t.Errorf("expect empty buffer, got %d bytes", l)
+	}
+	out.Reset()
+	_ = w.Close()
+	if strings.Contains(out.String(), "logger") {
+		t.Errorf("expect nothing logged on Close, got %q", out.String())
+	}
+}
+
+func TestWriterAt(t *testing.T) {
+	out := &bytes.Buffer{}
+	ctx := MDCPut(context.Background(), "lib", "redis")
+	w := WriterAt(buildStdLogger("lib", out), ctx, DebugLevel)
+	GetLevelStore().Set("lib", DebugLevel)
+	defer GetLevelStore().UnSet("lib")
+	_, _ = w.Write([]byte("connected\nretry"))
+	expect := "level=DEBUG logger=lib lib=redis connected\n"
+	if got := out.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+	out.Reset()
+	_, _ = w.Write([]byte("ing\n"))
+	expect = "level=DEBUG logger=lib lib=redis retrying\n"
+	if got := out.String(); got != expect {
+		t.Errorf("expect %q, got %q", expect, got)
+	}
+}
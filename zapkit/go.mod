@@ -0,0 +1,15 @@
+module github.com/go-kita/log/zapkit
+
+go 1.19
+
+require (
+	github.com/go-kita/log v0.0.0-00010101000000-000000000000
+	go.uber.org/zap v1.24.0
+)
+
+require (
+	go.uber.org/atomic v1.8.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+)
+
+replace github.com/go-kita/log => ../
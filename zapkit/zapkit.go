@@ -0,0 +1,88 @@
+// Package zapkit provides an OutPutter writing logs through zap.
+// It is a separated module so that the zap dependency is kept out of the core
+// logging module.
+package zapkit
+
+import (
+	"context"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-kita/log"
+)
+
+// outPutter is an OutPutter writing records through a zap core.
+type outPutter struct {
+	logger *zap.Logger
+}
+
+// NewZapOutPutter create a OutPutter writing records through the core of the
+// provided zap.Logger, so that a configured zap backend is reused. The fields
+// are converted by zap.Any, with Valuers resolved; the logger name is written
+// as the name of the zap entry instead of a field.
+//
+// The records are gated by both: the builtin Logger gates them with the
+// LevelStore before they reach the OutPutter, and the OutPutter defers to the
+// level of the zap core, checked before resolving any Valuer. Levels are
+// mapped by ZapLevel.
+// The core is used directly, so that PanicLevel and FatalLevel records are
+// written without zap panicking or exiting, which is done by the Printer
+// after printing. Options of the zap.Logger not belonging to the core, i.e.
+// zap.AddStacktrace, don't apply; the caller is always set to the entry.
+func NewZapOutPutter(l *zap.Logger) log.OutPutter {
+	if l == nil {
+		l = zap.NewNop()
+	}
+	return &outPutter{logger: l}
+}
+
+// ZapLevel maps a Level to a zapcore.Level: levels up to DebugLevel are
+// zapcore.DebugLevel, InfoLevel, WarnLevel and ErrorLevel are the zap levels
+// of the same names, PanicLevel is zapcore.PanicLevel and the higher are
+// zapcore.FatalLevel.
+func ZapLevel(level log.Level) zapcore.Level {
+	switch {
+	case level <= log.DebugLevel:
+		return zapcore.DebugLevel
+	case level == log.InfoLevel:
+		return zapcore.InfoLevel
+	case level == log.WarnLevel:
+		return zapcore.WarnLevel
+	case level == log.ErrorLevel:
+		return zapcore.ErrorLevel
+	case level == log.PanicLevel:
+		return zapcore.PanicLevel
+	default:
+		return zapcore.FatalLevel
+	}
+}
+
+func (o *outPutter) OutPut(
+	ctx context.Context, name string, level log.Level, msg string, fields []log.Field, callDepth int) {
+	entry := zapcore.Entry{
+		LoggerName: name,
+		Time:       log.Now(),
+		Level:      ZapLevel(level),
+		Message:    msg,
+	}
+	ce := o.logger.Core().Check(entry, nil)
+	if ce == nil {
+		return
+	}
+	// skip OutPut, the rest like the std OutPutter
+	ce.Caller = zapcore.NewEntryCaller(runtime.Caller(callDepth + 2))
+	zfs := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		if len(field.Key) == 0 || field.Key == log.LevelKey || field.Key == log.LoggerKey {
+			continue
+		}
+		zfs = append(zfs, zap.Any(field.Key, log.NamedValue(ctx, name, field.Value)))
+	}
+	ce.Write(zfs...)
+}
+
+func (o *outPutter) Describe() string {
+	return "zap"
+}
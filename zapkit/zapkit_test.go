@@ -0,0 +1,88 @@
+package zapkit
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/go-kita/log"
+)
+
+func TestNewZapOutPutter(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	logger := log.NewStdLogger("app", NewZapOutPutter(zap.New(core).With(zap.String("svc", "demo"))))
+	ctx := context.Background()
+	logger.AtLevel(ctx, log.InfoLevel).Print("hidden")
+	user := log.Valuer(func(ctx context.Context) interface{} { return "mike" })
+	logger.AtLevel(ctx, log.ErrorLevel).With("user", user).With("n", 1).Print("abc")
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("expect 1 entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Level != zapcore.ErrorLevel || got.Message != "abc" || got.LoggerName != "app" {
+		t.Errorf("unexpected entry: %+v", got.Entry)
+	}
+	if file := filepath.Base(got.Caller.File); file != "zapkit_test.go" {
+		t.Errorf("expect caller in %q, got %q", "zapkit_test.go", file)
+	}
+	expect := map[string]interface{}{"svc": "demo", "user": "mike", "n": int64(1)}
+	if fields := got.ContextMap(); !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expect %v, got %v", expect, fields)
+	}
+}
+
+// fixedClock is a log.Clock stopped at a time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func (c fixedClock) NewTicker(_ time.Duration) log.Ticker {
+	return nil
+}
+
+func TestNewZapOutPutter_Clock(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	log.UseClock(fixedClock(now))
+	defer log.UseClock(nil)
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := log.NewStdLogger("app", NewZapOutPutter(zap.New(core)))
+	logger.AtLevel(context.Background(), log.InfoLevel).Print("abc")
+	if entries := logs.All(); len(entries) != 1 || !entries[0].Time.Equal(now) {
+		t.Errorf("expect 1 entry at %v, got %v", now, entries)
+	}
+}
+
+func TestNewZapOutPutter_PanicLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	o := NewZapOutPutter(zap.New(core))
+	o.OutPut(context.Background(), "app", log.PanicLevel, "abc", nil, 0)
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Level != zapcore.PanicLevel {
+		t.Errorf("expect a PanicLevel entry written without panic, got %+v", entries)
+	}
+}
+
+func TestZapLevel(t *testing.T) {
+	tests := map[log.Level]zapcore.Level{
+		log.TraceLevel: zapcore.DebugLevel,
+		log.DebugLevel: zapcore.DebugLevel,
+		log.InfoLevel:  zapcore.InfoLevel,
+		log.WarnLevel:  zapcore.WarnLevel,
+		log.ErrorLevel: zapcore.ErrorLevel,
+		log.PanicLevel: zapcore.PanicLevel,
+		log.FatalLevel: zapcore.FatalLevel,
+	}
+	for level, expect := range tests {
+		if got := ZapLevel(level); got != expect {
+			t.Errorf("%v: expect %v, got %v", level, expect, got)
+		}
+	}
+}